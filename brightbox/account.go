@@ -0,0 +1,160 @@
+package brightbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+var accountRegexp = regexp.MustCompile("^acc-.....$")
+
+// resolveAccount finds the account the provider should operate upon.
+// The configured account may be an account ID or an account name. If
+// no account is configured, the credentials must have access to
+// exactly one account unless the first is explicitly requested.
+func resolveAccount(ctx context.Context, client *brightbox.Client, authd authdetails) (*brightbox.Account, error) {
+	if accountRegexp.MatchString(authd.Account) {
+		tflog.SubsystemInfo(ctx, logSubsystemAuth, "Checking credentials have access to account", map[string]interface{}{
+			"account_id": authd.Account,
+		})
+		account, err := client.Account(ctx, authd.Account)
+		if err != nil {
+			return nil, &accountError{
+				Summary: fmt.Sprintf("Unable to access account %v with supplied credentials", authd.Account),
+				err:     err,
+			}
+		}
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Account check passed")
+		return account, nil
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystemAuth, "Obtaining accessible accounts")
+	accounts, err := client.Accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, &accountError{Summary: "The supplied credentials do not have access to any accounts"}
+	}
+
+	if authd.Account != "" {
+		tflog.SubsystemInfo(ctx, logSubsystemAuth, "Looking up account by name", map[string]interface{}{
+			"account_name": authd.Account,
+		})
+		var matches []brightbox.Account
+		for _, account := range accounts {
+			if account.Name == authd.Account {
+				matches = append(matches, account)
+			}
+		}
+		switch len(matches) {
+		case 1:
+			tflog.SubsystemDebug(ctx, logSubsystemAuth, "Found account by name", map[string]interface{}{
+				"account_name": authd.Account,
+				"account_id":   matches[0].ID,
+			})
+			return &matches[0], nil
+		case 0:
+			return nil, accountChoiceError(
+				fmt.Sprintf("No account named %q is accessible with the supplied credentials", authd.Account),
+				"Set account to one of the following IDs or names:",
+				accounts,
+			)
+		default:
+			return nil, accountChoiceError(
+				fmt.Sprintf("More than one account is named %q", authd.Account),
+				"Set account to one of the following IDs instead:",
+				matches,
+			)
+		}
+	}
+
+	if len(accounts) > 1 && !authd.UseFirstAccount {
+		return nil, accountChoiceError(
+			"Account must be specified",
+			"The supplied credentials have access to more than one account. "+
+				"Set account to one of the following IDs or names, or set use_first_account to true:",
+			accounts,
+		)
+	}
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Using default account", map[string]interface{}{
+		"account_id": accounts[0].ID,
+	})
+	return &accounts[0], nil
+}
+
+// accountError explains why an account could not be selected
+type accountError struct {
+	Summary string
+	Detail  string
+	err     error
+}
+
+func (e *accountError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s: %s", e.Summary, e.err)
+	}
+	return e.Summary
+}
+
+func (e *accountError) Unwrap() error {
+	return e.err
+}
+
+func (e *accountError) Diagnostic() diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  e.Summary,
+		Detail:   e.Detail,
+	}
+}
+
+func accountChoiceError(summary string, detail string, accounts []brightbox.Account) error {
+	var list strings.Builder
+	for _, account := range accounts {
+		fmt.Fprintf(&list, "\n  %s  %s", account.ID, account.Name)
+	}
+	return &accountError{
+		Summary: summary,
+		Detail:  detail + list.String(),
+	}
+}
+
+// isForbidden reports whether the API refused a request because the
+// credentials lack permission
+func isForbidden(err error) bool {
+	var apierror *brightbox.APIError
+	return errors.As(err, &apierror) && apierror.StatusCode == http.StatusForbidden
+}
+
+// checkAccountPermitted guards against operating on the wrong account
+// by checking the selected account against the configured allow and
+// deny lists
+func checkAccountPermitted(authd authdetails, account *brightbox.Account) error {
+	if len(authd.AllowedAccountIDs) > 0 && !strSliceContains(authd.AllowedAccountIDs, account.ID) {
+		return &accountError{
+			Summary: fmt.Sprintf("Account %v is not an allowed account", account.ID),
+			Detail: fmt.Sprintf(
+				"The provider selected account %v (%s), which is not in allowed_account_ids %v. Check the account setting and the BRIGHTBOX_ACCOUNT environment variable.",
+				account.ID, account.Name, authd.AllowedAccountIDs,
+			),
+		}
+	}
+	if strSliceContains(authd.ForbiddenAccountIDs, account.ID) {
+		return &accountError{
+			Summary: fmt.Sprintf("Account %v is a forbidden account", account.ID),
+			Detail: fmt.Sprintf(
+				"The provider selected account %v (%s), which is listed in forbidden_account_ids. Check the account setting and the BRIGHTBOX_ACCOUNT environment variable.",
+				account.ID, account.Name,
+			),
+		}
+	}
+	return nil
+}
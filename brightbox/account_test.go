@@ -0,0 +1,200 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/oauth2"
+	"gotest.tools/v3/assert"
+)
+
+type fakeAccount struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// newFakeAccountClient returns an API client talking to a server
+// that knows about the given accounts
+func newFakeAccountClient(t *testing.T, accounts ...fakeAccount) *brightbox.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/1.0/accounts" {
+			json.NewEncoder(w).Encode(accounts)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/1.0/accounts/")
+		for _, account := range accounts {
+			if account.ID == id {
+				json.NewEncoder(w).Encode(account)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error_name":"forbidden","errors":["Not permitted"]}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := brightbox.Connect(context.Background(), &connectedCredentials{
+		Config:      endpoint.Config{BaseURL: server.URL},
+		client:      server.Client(),
+		tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	})
+	assert.NilError(t, err)
+	return client
+}
+
+func TestResolveAccount(t *testing.T) {
+	production := fakeAccount{ID: "acc-prod1", Name: "Production", Status: "active"}
+	staging := fakeAccount{ID: "acc-stag1", Name: "Staging", Status: "active"}
+	clash := fakeAccount{ID: "acc-stag2", Name: "Staging", Status: "active"}
+
+	testCases := []struct {
+		name     string
+		accounts []fakeAccount
+		authd    authdetails
+		result   string
+		err      string
+		detail   string
+	}{
+		{
+			name:     "by id",
+			accounts: []fakeAccount{production, staging},
+			authd:    authdetails{Account: "acc-stag1"},
+			result:   "acc-stag1",
+		},
+		{
+			name:     "inaccessible id",
+			accounts: []fakeAccount{production},
+			authd:    authdetails{Account: "acc-stag1"},
+			err:      "Unable to access account acc-stag1 with supplied credentials",
+		},
+		{
+			name:     "by name",
+			accounts: []fakeAccount{production, staging},
+			authd:    authdetails{Account: "Staging"},
+			result:   "acc-stag1",
+		},
+		{
+			name:     "unknown name",
+			accounts: []fakeAccount{production, staging},
+			authd:    authdetails{Account: "Testing"},
+			err:      `No account named "Testing" is accessible with the supplied credentials`,
+			detail:   "acc-prod1  Production",
+		},
+		{
+			name:     "ambiguous name",
+			accounts: []fakeAccount{production, staging, clash},
+			authd:    authdetails{Account: "Staging"},
+			err:      `More than one account is named "Staging"`,
+			detail:   "acc-stag2  Staging",
+		},
+		{
+			name:     "single account",
+			accounts: []fakeAccount{staging},
+			result:   "acc-stag1",
+		},
+		{
+			name:     "several accounts",
+			accounts: []fakeAccount{production, staging},
+			err:      "Account must be specified",
+			detail:   "acc-stag1  Staging",
+		},
+		{
+			name:     "several accounts using first",
+			accounts: []fakeAccount{production, staging},
+			authd:    authdetails{UseFirstAccount: true},
+			result:   "acc-prod1",
+		},
+		{
+			name: "no accounts",
+			err:  "The supplied credentials do not have access to any accounts",
+		},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			client := newFakeAccountClient(t, tcase.accounts...)
+			account, err := resolveAccount(context.Background(), client, tcase.authd)
+			if tcase.err != "" {
+				assert.Assert(t, err != nil)
+				result := brightboxFromErr(err)
+				assert.Equal(t, result.Summary, tcase.err)
+				assert.Assert(t, strings.Contains(result.Detail, tcase.detail), result.Detail)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, account.ID, tcase.result)
+		})
+	}
+}
+
+func TestCheckAccountPermitted(t *testing.T) {
+	account := &brightbox.Account{ID: "acc-stag1", Name: "Staging"}
+	testCases := []struct {
+		name  string
+		authd authdetails
+		err   string
+	}{
+		{name: "no lists"},
+		{name: "allowed", authd: authdetails{AllowedAccountIDs: []string{"acc-prod1", "acc-stag1"}}},
+		{name: "not forbidden", authd: authdetails{ForbiddenAccountIDs: []string{"acc-prod1"}}},
+		{
+			name:  "not allowed",
+			authd: authdetails{AllowedAccountIDs: []string{"acc-prod1"}},
+			err:   "Account acc-stag1 is not an allowed account",
+		},
+		{
+			name:  "forbidden",
+			authd: authdetails{ForbiddenAccountIDs: []string{"acc-stag1"}},
+			err:   "Account acc-stag1 is a forbidden account",
+		},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			err := checkAccountPermitted(tcase.authd, account)
+			if tcase.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Assert(t, err != nil)
+			assert.Equal(t, brightboxFromErr(err).Summary, tcase.err)
+		})
+	}
+}
+
+func TestForbiddenAccountFailsFirstRead(t *testing.T) {
+	server := newFakeTokenServer(t)
+	var requests []string
+	var apiRequests int32
+	server.Config.Handler = countingHandler(server.Config.Handler, &apiRequests, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/1.0/accounts/acc-12345" {
+			w.Write([]byte(`{"id":"acc-12345","name":"Production","status":"active"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"grp-12345","name":"web"}`))
+	})
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient:           "cli-12345",
+		APISecret:           "secret",
+		Account:             "acc-12345",
+		APIURL:              server.URL,
+		OrbitURL:            server.URL + "/",
+		ForbiddenAccountIDs: []string{"acc-12345"},
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+
+	group := Provider("test").ResourcesMap["brightbox_server_group"]
+	d := group.Data(&terraform.InstanceState{ID: "grp-12345"})
+	diags = group.ReadContext(context.Background(), d, composite)
+	assert.Assert(t, diags.HasError())
+	assert.Equal(t, diags[0].Summary, "Account acc-12345 is a forbidden account")
+	assert.DeepEqual(t, requests, []string{"/1.0/accounts/acc-12345"})
+}
@@ -2,8 +2,6 @@ package brightbox
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
@@ -12,86 +10,175 @@ import (
 	"github.com/brightbox/gobrightbox/v2/enums/accountstatus"
 	"github.com/brightbox/gobrightbox/v2/passwordcredentials"
 	"github.com/gophercloud/gophercloud"
-	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/oauth2"
 )
 
-func authenticatedClients(authCtx context.Context, authd authdetails) (*brightbox.Client, *gophercloud.ServiceClient, diag.Diagnostics) {
-	apiContext, apiCancel := context.WithCancel(context.Background())
-	defer apiCancel()
-	apiContext = contextWithLoggedHTTPClient(apiContext)
+// authSession is the result of authenticating with the API: the
+// selected account and credentials that can be reused by each client
+type authSession struct {
+	credentials *connectedCredentials
+	lookup      *brightbox.Client
+	account     *brightbox.Account
+	storageOnly bool
+}
+
+// authenticate obtains a token and selects the account to operate
+// upon. Credentials that only grant storage access are accepted, but
+// the session is marked as storage only.
+func authenticate(authCtx context.Context, authd authdetails, httpClient *http.Client) (*authSession, error) {
+	// Token sources hold on to this context and use it whenever they
+	// refresh, so it has to last as long as the provider does. It keeps
+	// the loggers from authCtx but never its cancellation.
+	tokenContext := contextWithHTTPClient(context.WithoutCancel(authCtx), httpClient)
 
-	log.Printf("[DEBUG] Fetching Infrastructure Client")
-	client, err := brightbox.Connect(apiContext, confFromAuthd(authd))
+	tflog.SubsystemDebug(authCtx, logSubsystemAuth, "Authenticating")
+	conf, err := confFromAuthd(tokenContext, authd)
 	if err != nil {
-		return nil, nil, brightboxFromErrSlice(err)
+		return nil, err
+	}
+	oauthClient, tokenSource, err := conf.Client(tokenContext)
+	if err != nil {
+		return nil, err
+	}
+	session := &authSession{
+		credentials: &connectedCredentials{
+			Config: endpoint.Config{
+				BaseURL: authd.APIURL,
+			},
+			client:      oauthClient,
+			tokenSource: tokenSource,
+		},
+	}
+	session.lookup, err = brightbox.Connect(tokenContext, session.credentials)
+	if err != nil {
+		return nil, err
 	}
 
-	var diags diag.Diagnostics
-
-	if authd.Account == "" {
-		log.Printf("[INFO] Obtaining default account")
-
-		accounts, err := client.Accounts(authCtx)
-		if err != nil {
-			return nil, nil, brightboxFromErrSlice(err)
+	session.account, err = resolveAccount(authCtx, session.lookup, authd)
+	switch {
+	case err == nil:
+		warnIfInactive(authCtx, session.account)
+	case authd.UserName == "" && isForbidden(err):
+		if !accountRegexp.MatchString(authd.Account) {
+			return nil, &accountError{
+				Summary: "Unable to look up the account with supplied credentials",
+				Detail: "An API client in the storage permissions group cannot look up its account. " +
+					"If these credentials only grant storage access, set account to the ID of the account the API client belongs to.",
+				err: err,
+			}
 		}
-		authd.Account = accounts[0].ID
-		log.Printf("[DEBUG] default account is %v", authd.Account)
-		diags = checkIsActive(diags, &accounts[0])
-	} else {
-		log.Printf("[INFO] Checking credentials have access to %v", authd.Account)
-		account, err := client.Account(authCtx, authd.Account)
-		if err != nil {
-			return nil, nil, diag.Errorf("Unable to access account %v with supplied credentials", authd.Account)
+		// A full access client pointed at the wrong account is
+		// forbidden too, so only treat these credentials as storage
+		// only if they can reach the account's storage
+		if !canAccessOrbit(authCtx, authd, httpClient, tokenSource) {
+			return nil, err
 		}
-		log.Printf("[DEBUG] account check passsed")
-		diags = checkIsActive(diags, account)
+		tflog.SubsystemInfo(authCtx, logSubsystemAuth, "Credentials only grant storage access, skipping infrastructure account checks")
+		session.storageOnly = true
+		session.account = &brightbox.Account{ID: authd.Account}
+	default:
+		return nil, err
 	}
+	if err := checkAccountPermitted(authd, session.account); err != nil {
+		return nil, err
+	}
+	tflog.SubsystemInfo(authCtx, logSubsystemAuth, "Operating on account", map[string]interface{}{
+		"account_id": session.account.ID,
+	})
+	return session, nil
+}
 
-	log.Printf("[DEBUG] Building Orbit Client")
+// canAccessOrbit reports whether the token grants access to the
+// Orbit storage of the configured account
+func canAccessOrbit(ctx context.Context, authd authdetails, httpClient *http.Client, tokenSource oauth2.TokenSource) bool {
 	oe, err := orbitEndpointFromAuthd(authd)
 	if err != nil {
-		return nil, nil, append(diags, brightboxFromErr(err))
+		return false
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, oe, nil)
+	if err != nil {
+		return false
 	}
+	req.Header.Set("X-Auth-Token", token.AccessToken)
+	res, err := httpClient.Do(req)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Orbit access check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return false
+	}
+	defer res.Body.Close()
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Orbit access check", map[string]interface{}{
+		"http_status_code": res.StatusCode,
+	})
+	return res.StatusCode >= 200 && res.StatusCode <= 299
+}
+
+// apiClient builds the infrastructure client for the session account
+func (s *authSession) apiClient(authd authdetails, httpClient *http.Client) (*brightbox.Client, error) {
+	if s.storageOnly {
+		return nil, errStorageOnly
+	}
+	credentials := *s.credentials
+	// User credentials can reach several accounts, so scope the
+	// client to the one selected
+	if authd.UserName != "" {
+		credentials.Account = s.account.ID
+	}
+	return brightbox.Connect(contextWithHTTPClient(context.Background(), httpClient), &credentials)
+}
 
-	storageContext, storageCancel := context.WithCancel(context.Background())
-	defer storageCancel()
-	storageContext = contextWithLoggedHTTPClient(storageContext)
-	orbit, err := orbitServiceClient(storageContext, client, oe)
+// orbitClient builds the Orbit client for the session account
+func (s *authSession) orbitClient(authd authdetails, httpClient *http.Client) (*gophercloud.ServiceClient, error) {
+	authd.Account = s.account.ID
+	oe, err := orbitEndpointFromAuthd(authd)
 	if err != nil {
-		diags = append(diags, brightboxFromErr(err))
+		return nil, err
 	}
-	return client, orbit, diags
+	return orbitServiceClient(httpClient, s.credentials.tokenSource, oe)
 }
 
-func checkIsActive(diags diag.Diagnostics, account *brightbox.Account) diag.Diagnostics {
+func warnIfInactive(ctx context.Context, account *brightbox.Account) {
 	if account.Status == accountstatus.Active {
-		return diags
+		return
 	}
-	return append(diags,
-		diag.Diagnostic{
-			Severity: diag.Warning,
-			Summary:  fmt.Sprintf("Account %v is not active", account.ID),
-			Detail:   fmt.Sprintf("The account %v is showing state %v\nIf this is unexpected, please use the GUI to contact Brightbox Support", account.ID, account.Status),
-		},
-	)
+	tflog.SubsystemWarn(ctx, logSubsystemAuth, "The account is not active. If this is unexpected, please use the GUI to contact Brightbox Support", map[string]interface{}{
+		"account_id":     account.ID,
+		"account_status": account.Status.String(),
+	})
 }
 
-func orbitServiceClient(serviceContext context.Context, client *brightbox.Client, endpoint string) (*gophercloud.ServiceClient, error) {
+// orbitServiceClient builds a gophercloud client for Orbit that takes
+// its token from the same source as the API client, so expiring tokens
+// are refreshed rather than reused
+func orbitServiceClient(httpClient *http.Client, tokenSource oauth2.TokenSource, endpoint string) (*gophercloud.ServiceClient, error) {
 	pc := &gophercloud.ProviderClient{}
-	if httpClient, ok := serviceContext.Value(oauth2.HTTPClient).(*http.Client); ok {
-		pc.HTTPClient = *httpClient
+	pc.HTTPClient = *httpClient
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
 	}
-	err := pc.SetTokenAndAuthResult(client)
-	if err != nil {
-		return nil, err
+	pc.HTTPClient.Transport = &orbitTokenTransport{
+		base:   base,
+		source: tokenSource,
 	}
-	pc.ReauthFunc = func() error {
-		return pc.SetTokenAndAuthResult(pc.GetAuthResult())
+	setToken := func() error {
+		token, err := tokenSource.Token()
+		if err != nil {
+			return err
+		}
+		pc.SetToken(token.AccessToken)
+		return nil
+	}
+	if err := setToken(); err != nil {
+		return nil, err
 	}
+	pc.ReauthFunc = setToken
 
 	return &gophercloud.ServiceClient{
 		ProviderClient: pc,
@@ -99,6 +186,23 @@ func orbitServiceClient(serviceContext context.Context, client *brightbox.Client
 	}, nil
 }
 
+// orbitTokenTransport sets a current token on every Orbit request,
+// replacing the one gophercloud last stored
+type orbitTokenTransport struct {
+	base   http.RoundTripper
+	source oauth2.TokenSource
+}
+
+func (t *orbitTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Auth-Token", token.AccessToken)
+	return t.base.RoundTrip(req)
+}
+
 func orbitEndpointFromAuthd(authd authdetails) (string, error) {
 	conf := &endpoint.Config{
 		BaseURL: authd.OrbitURL,
@@ -107,7 +211,40 @@ func orbitEndpointFromAuthd(authd authdetails) (string, error) {
 	return conf.StorageURL()
 }
 
-func confFromAuthd(authd authdetails) brightbox.Oauth2 {
+func confFromAuthd(ctx context.Context, authd authdetails) (brightbox.Oauth2, error) {
+	if authd.accessToken != "" {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Using supplied access token")
+		return &tokenCredentials{
+			Config: endpoint.Config{BaseURL: authd.APIURL},
+			source: staticTokenSource(authd.accessToken),
+		}, nil
+	}
+	if authd.CredentialProcess != "" {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Using credential process")
+		return &tokenCredentials{
+			Config: endpoint.Config{BaseURL: authd.APIURL},
+			source: newProcessTokenSource(ctx, authd.CredentialProcess),
+		}, nil
+	}
+	if authd.TokenCache {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Using token cache")
+		cache, err := newTokenCache(authd.TokenCacheFile)
+		if err != nil {
+			return nil, err
+		}
+		conf := &cachedCredentials{
+			Config: endpoint.Config{
+				BaseURL: authd.APIURL,
+				Scopes:  endpoint.FullScope,
+			},
+			authd: authd,
+			cache: cache,
+		}
+		if authd.UserName != "" {
+			conf.Account = authd.Account
+		}
+		return conf, nil
+	}
 	if authd.UserName != "" || authd.password != "" {
 		return &passwordcredentials.Config{
 			UserName: authd.UserName,
@@ -119,7 +256,7 @@ func confFromAuthd(authd authdetails) brightbox.Oauth2 {
 				Account: authd.Account,
 				Scopes:  endpoint.FullScope,
 			},
-		}
+		}, nil
 	}
 	return &clientcredentials.Config{
 		ID:     authd.APIClient,
@@ -128,14 +265,22 @@ func confFromAuthd(authd authdetails) brightbox.Oauth2 {
 			BaseURL: authd.APIURL,
 			Scopes:  endpoint.FullScope,
 		},
-	}
+	}, nil
 }
 
-func contextWithLoggedHTTPClient(ctx context.Context) context.Context {
-	client := cleanhttp.DefaultClient()
-	if logging.IsDebugOrHigher() {
-		log.Printf("[DEBUG] Enabling HTTP requests/responses tracing")
-		client.Transport = logging.NewTransport("Brightbox", client.Transport)
-	}
+// connectedCredentials reuses an authenticated HTTP client so the API
+// client can be rebuilt for another account without fetching a new
+// token
+type connectedCredentials struct {
+	endpoint.Config
+	client      *http.Client
+	tokenSource oauth2.TokenSource
+}
+
+func (c *connectedCredentials) Client(ctx context.Context) (*http.Client, oauth2.TokenSource, error) {
+	return c.client, c.tokenSource, nil
+}
+
+func contextWithHTTPClient(ctx context.Context, client *http.Client) context.Context {
 	return context.WithValue(ctx, oauth2.HTTPClient, client)
 }
@@ -0,0 +1,206 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"gotest.tools/v3/assert"
+)
+
+// newStorageOnlyServer mimics the API as seen by an API client in the
+// storage permissions group
+func newStorageOnlyServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token/" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "storage-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/v1/acc-12345") && r.Header.Get("X-Auth-Token") == "storage-token" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error_name":"forbidden","errors":["Storage clients cannot access this resource"]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConfigureStorageOnlyClient(t *testing.T) {
+	server := newStorageOnlyServer(t)
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		Account:   "acc-12345",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+
+	orbit, err := composite.OrbitClient()
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasSuffix(orbit.ResourceBaseURL(), "/v1/acc-12345/"), orbit.ResourceBaseURL())
+
+	_, err = composite.APIClient()
+	assert.ErrorIs(t, err, errStorageOnly)
+	assert.Equal(t, brightboxFromErr(err).Summary, "These credentials only grant storage access")
+}
+
+func TestConfigureStorageOnlyClientNeedsAccountID(t *testing.T) {
+	server := newStorageOnlyServer(t)
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	_, err := composite.OrbitClient()
+	assert.Assert(t, err != nil)
+	assert.Equal(t, brightboxFromErr(err).Summary, "Unable to look up the account with supplied credentials")
+}
+
+func TestConfigureForbiddenAccountIsNotStorageOnly(t *testing.T) {
+	server := newStorageOnlyServer(t)
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		Account:   "acc-54321",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	_, err := composite.APIClient()
+	assert.Assert(t, err != nil)
+	assert.Assert(t, !errors.Is(err, errStorageOnly), err)
+	assert.Assert(t, isForbidden(err), err)
+	assert.Equal(t, brightboxFromErr(err).Summary, "Unable to access account acc-54321 with supplied credentials")
+}
+
+func TestConfigureClientIsLazy(t *testing.T) {
+	server := newFakeTokenServer(t)
+	var apiRequests int32
+	server.Config.Handler = countingHandler(server.Config.Handler, &apiRequests, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/1.0/accounts":
+			w.Write([]byte(`[{"id":"acc-12345","name":"Example","status":"active"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	assert.Equal(t, atomic.LoadInt32(&server.issued), int32(0))
+	assert.Equal(t, atomic.LoadInt32(&apiRequests), int32(0))
+
+	client, err := composite.APIClient()
+	assert.NilError(t, err)
+	assert.Equal(t, atomic.LoadInt32(&server.issued), int32(1))
+	assert.Equal(t, atomic.LoadInt32(&apiRequests), int32(1))
+
+	again, err := composite.APIClient()
+	assert.NilError(t, err)
+	assert.Equal(t, again, client)
+
+	orbit, err := composite.OrbitClient()
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasSuffix(orbit.ResourceBaseURL(), "/v1/acc-12345/"), orbit.ResourceBaseURL())
+	assert.Equal(t, atomic.LoadInt32(&server.issued), int32(1))
+	assert.Equal(t, atomic.LoadInt32(&apiRequests), int32(1))
+}
+
+// countingHandler passes token requests to tokens and counts and
+// serves everything else with api
+func countingHandler(tokens http.Handler, count *int32, api http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token/" {
+			tokens.ServeHTTP(w, r)
+			return
+		}
+		atomic.AddInt32(count, 1)
+		api(w, r)
+	})
+}
+
+func TestUnconfiguredClient(t *testing.T) {
+	composite := unconfiguredClient()
+	assert.Assert(t, !composite.ConfigKnown())
+	_, err := composite.APIClient()
+	assert.ErrorIs(t, err, errConfigUnknown)
+	_, err = composite.OrbitClient()
+	assert.ErrorIs(t, err, errConfigUnknown)
+}
+
+func TestClientsRefreshExpiredTokens(t *testing.T) {
+	server := newFakeTokenServer(t)
+	// oauth2 treats tokens as expired ten seconds early, so these
+	// last for about a second
+	server.expiresIn = 11
+	var apiRequests int32
+	current := func() string {
+		return fmt.Sprintf("access-%d", atomic.LoadInt32(&server.issued))
+	}
+	server.Config.Handler = countingHandler(server.Config.Handler, &apiRequests, func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if strings.HasPrefix(r.URL.Path, "/v1/") {
+			token = r.Header.Get("X-Auth-Token")
+		}
+		if token != current() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/1.0/accounts":
+			w.Write([]byte(`[{"id":"acc-12345","name":"Example","status":"active"}]`))
+		case "/1.0/servers":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	client, err := composite.APIClient()
+	assert.NilError(t, err)
+	orbit, err := composite.OrbitClient()
+	assert.NilError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Servers(context.Background())
+		assert.NilError(t, err)
+		_, err = orbit.Head(orbit.ResourceBaseURL(), &gophercloud.RequestOpts{OkCodes: []int{http.StatusNoContent}})
+		assert.NilError(t, err)
+		time.Sleep(1100 * time.Millisecond)
+	}
+	_, err = client.Servers(context.Background())
+	assert.NilError(t, err)
+	assert.Assert(t, atomic.LoadInt32(&server.issued) >= 3, "only %d tokens issued", server.issued)
+}
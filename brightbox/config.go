@@ -2,34 +2,152 @@ package brightbox
 
 import (
 	"context"
-	"log"
+	"errors"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/brightbox/gobrightbox/v2/endpoint"
 	"github.com/gophercloud/gophercloud"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
 const (
-	defaultClientID     = "app-dkmch"
-	defaultClientSecret = "uogoelzgt0nwawb"
-	clientEnvVar        = "BRIGHTBOX_CLIENT"
-	clientSecretEnvVar  = "BRIGHTBOX_CLIENT_SECRET"
-	usernameEnvVar      = "BRIGHTBOX_USER_NAME"
-	passwordEnvVar      = "BRIGHTBOX_PASSWORD"
-	accountEnvVar       = "BRIGHTBOX_ACCOUNT"
-	apiURLEnvVar        = "BRIGHTBOX_API_URL"
-	orbitURLEnvVar      = "BRIGHTBOX_ORBIT_URL"
+	defaultClientID         = "app-dkmch"
+	defaultClientSecret     = "uogoelzgt0nwawb"
+	clientEnvVar            = "BRIGHTBOX_CLIENT"
+	clientSecretEnvVar      = "BRIGHTBOX_CLIENT_SECRET"
+	usernameEnvVar          = "BRIGHTBOX_USER_NAME"
+	passwordEnvVar          = "BRIGHTBOX_PASSWORD"
+	accountEnvVar           = "BRIGHTBOX_ACCOUNT"
+	apiURLEnvVar            = "BRIGHTBOX_API_URL"
+	orbitURLEnvVar          = "BRIGHTBOX_ORBIT_URL"
+	profileEnvVar           = "BRIGHTBOX_PROFILE"
+	regionEnvVar            = "BRIGHTBOX_REGION"
+	tokenCacheEnvVar        = "BRIGHTBOX_TOKEN_CACHE"
+	tokenCacheFileEnvVar    = "BRIGHTBOX_TOKEN_CACHE_FILE"
+	accessTokenEnvVar       = "BRIGHTBOX_ACCESS_TOKEN"
+	credentialProcessEnvVar = "BRIGHTBOX_CREDENTIAL_PROCESS"
+	httpProxyEnvVar         = "BRIGHTBOX_HTTP_PROXY"
+	caBundleEnvVar          = "BRIGHTBOX_CA_BUNDLE"
+	userAgentSuffixEnvVar   = "BRIGHTBOX_USER_AGENT_SUFFIX"
 
 	defaultTimeoutSeconds = 10
 	appPrefix             = "app-"
 )
 
-// CompositeClient allows access to Honcho and Orbit
+// CompositeClient allows access to Honcho and Orbit. Each client is
+// built the first time it is needed, so configurations that use
+// neither never authenticate.
 type CompositeClient struct {
-	APIClient   *brightbox.Client
-	OrbitClient *gophercloud.ServiceClient
+	DefaultZone string
+	// DefaultTimeouts replace the resource timeouts, keyed by
+	// operation, when a resource has no timeouts block
+	DefaultTimeouts map[string]time.Duration
+
+	authd      authdetails
+	httpClient *http.Client
+	// logCtx carries the provider loggers to authentication and
+	// token refreshes, which outlive the request that starts them
+	logCtx context.Context
+
+	sessionOnce sync.Once
+	session     *authSession
+	sessionErr  error
+	// configUnknown is set when the provider configuration was not
+	// yet known, so the clients cannot be built
+	configUnknown bool
+
+	apiOnce   sync.Once
+	apiClient *brightbox.Client
+	apiErr    error
+
+	orbitOnce   sync.Once
+	orbitClient *gophercloud.ServiceClient
+	orbitErr    error
+}
+
+var (
+	// errStorageOnly is returned when infrastructure access is
+	// requested with credentials restricted to Orbit
+	errStorageOnly = errors.New("these credentials only grant storage access")
+	// errConfigUnknown is returned when the provider was configured
+	// with values that were not yet known
+	errConfigUnknown = errors.New("the provider configuration depends on values that are not known until apply")
+)
+
+// APIClient returns the client for the Brightbox infrastructure API
+func (c *CompositeClient) APIClient() (*brightbox.Client, error) {
+	c.apiOnce.Do(func() {
+		session, err := c.authSession()
+		if err != nil {
+			c.apiErr = err
+			return
+		}
+		c.apiClient, c.apiErr = session.apiClient(c.authd, c.httpClient)
+		if c.apiErr == nil {
+			tflog.SubsystemInfo(c.logCtx, logSubsystemAPI, "Brightbox Client configured", map[string]interface{}{
+				"url": c.apiClient.ResourceBaseURL().String(),
+			})
+		}
+	})
+	return c.apiClient, c.apiErr
+}
+
+// OrbitClient returns the client for the Orbit storage service
+func (c *CompositeClient) OrbitClient() (*gophercloud.ServiceClient, error) {
+	c.orbitOnce.Do(func() {
+		session, err := c.authSession()
+		if err != nil {
+			c.orbitErr = err
+			return
+		}
+		c.orbitClient, c.orbitErr = session.orbitClient(c.authd, c.httpClient)
+		if c.orbitErr == nil {
+			tflog.SubsystemInfo(c.logCtx, logSubsystemOrbit, "Orbit Client configured", map[string]interface{}{
+				"url": c.orbitClient.ResourceBaseURL(),
+			})
+		}
+	})
+	return c.orbitClient, c.orbitErr
+}
+
+func (c *CompositeClient) authSession() (*authSession, error) {
+	c.sessionOnce.Do(func() {
+		if c.sessionErr != nil {
+			return
+		}
+		c.session, c.sessionErr = authenticate(c.logCtx, c.authd, c.httpClient)
+	})
+	return c.session, c.sessionErr
+}
+
+// unconfiguredClient stands in for a client whose configuration is
+// not yet known, failing only if a resource actually needs it
+func unconfiguredClient() *CompositeClient {
+	return &CompositeClient{sessionErr: errConfigUnknown, configUnknown: true}
+}
+
+// DefaultTimeout returns the provider default for the operation named
+// by key, falling back to the resource default when there is none
+func (c *CompositeClient) DefaultTimeout(key string) time.Duration {
+	if c != nil {
+		if timeout, ok := c.DefaultTimeouts[key]; ok {
+			return timeout
+		}
+	}
+	return defaultTimeout
+}
+
+// ConfigKnown reports whether the provider configuration was known
+// when the client was built. Until it is, resources keep their prior
+// state rather than refreshing it.
+func (c *CompositeClient) ConfigKnown() bool {
+	return !c.configUnknown
 }
 
 type authdetails struct {
@@ -40,44 +158,78 @@ type authdetails struct {
 	Account   string
 	APIURL    string
 	OrbitURL  string
+	Profile   string
+	Region    string
+
+	UseFirstAccount     bool
+	AllowedAccountIDs   []string
+	ForbiddenAccountIDs []string
+
+	TokenCache     bool
+	TokenCacheFile string
+
+	accessToken       string
+	CredentialProcess string
+
+	Transport transportdetails
 }
 
 // obtainCloudClient creates a new Composite client using details from
 // the environment
 func obtainCloudClient() (*CompositeClient, diag.Diagnostics) {
-	log.Printf("[DEBUG] obtainCloudClient")
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	return configureClient(
 		ctx,
 		authdetails{
-			APIClient: getenvWithDefault(clientEnvVar,
-				defaultClientID),
-			APISecret: getenvWithDefault(clientSecretEnvVar,
-				defaultClientSecret),
-			UserName: os.Getenv(usernameEnvVar),
-			password: os.Getenv(passwordEnvVar),
-			Account:  os.Getenv(accountEnvVar),
-			APIURL:   getenvWithDefault(apiURLEnvVar, ""),
-			OrbitURL: getenvWithDefault(orbitURLEnvVar, ""),
+			APIClient: os.Getenv(clientEnvVar),
+			APISecret: os.Getenv(clientSecretEnvVar),
+			UserName:  os.Getenv(usernameEnvVar),
+			password:  os.Getenv(passwordEnvVar),
+			Account:   os.Getenv(accountEnvVar),
+			APIURL:    os.Getenv(apiURLEnvVar),
+			OrbitURL:  os.Getenv(orbitURLEnvVar),
+			Profile:   os.Getenv(profileEnvVar),
+			Region:    os.Getenv(regionEnvVar),
+
+			TokenCache:     getenvBool(tokenCacheEnvVar),
+			TokenCacheFile: os.Getenv(tokenCacheFileEnvVar),
+
+			accessToken:       os.Getenv(accessTokenEnvVar),
+			CredentialProcess: os.Getenv(credentialProcessEnvVar),
+
+			Transport: transportdetails{
+				MaxRetries:   defaultMaxRetries,
+				RetryMaxWait: defaultRetryMaxWait * time.Second,
+
+				HTTPProxy: os.Getenv(httpProxyEnvVar),
+				CABundle:  os.Getenv(caBundleEnvVar),
+			},
 		},
 	)
 }
 
 // Validate account config entries
-func validateConfig(authd authdetails) diag.Diagnostics {
+func validateConfig(ctx context.Context, authd authdetails) diag.Diagnostics {
 	var result diag.Diagnostics
-	log.Printf("[DEBUG] Validating Config")
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Validating Config")
+	if authd.accessToken != "" || authd.CredentialProcess != "" {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Detected externally issued token")
+		if authd.accessToken != "" && authd.CredentialProcess != "" {
+			result = append(result, diag.Errorf("Only one of access_token and credential_process can be set")...)
+		}
+		if authd.UserName != "" || authd.password != "" {
+			result = append(result, diag.Errorf("User Credentials should be blank with access_token or credential_process")...)
+		}
+		return result
+	}
 	if strings.HasPrefix(authd.APIClient, appPrefix) {
-		log.Printf("[DEBUG] Detected OAuth Application. Validating User details.")
-		if authd.UserName == "" || authd.password == "" {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Detected OAuth Application. Validating User details")
+		if authd.UserName == "" || (authd.password == "" && !authd.TokenCache) {
 			result = append(result, diag.Errorf("User Credentials are missing. Please supply a Username and One Time Authentication code")...)
 		}
-		if authd.Account == "" {
-			result = append(result, diag.Errorf("Must specify Account with User Credentials")...)
-		}
 	} else {
-		log.Printf("[DEBUG] Detected API Client.")
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Detected API Client")
 		if authd.UserName != "" || authd.password != "" {
 			result = append(result, diag.Errorf("User Credentials should be blank with an API Client")...)
 		}
@@ -85,25 +237,52 @@ func validateConfig(authd authdetails) diag.Diagnostics {
 	return result
 }
 
-func configureClient(ctx context.Context, authd authdetails) (*CompositeClient, diag.Diagnostics) {
-	log.Printf("[DEBUG] Configuring Brightbox Clients")
-	if err := validateConfig(authd); err.HasError() {
-		return nil, err
+// Fill in any details not explicitly configured with the built in
+// defaults
+func applyDefaults(authd authdetails) authdetails {
+	if authd.APIClient == "" {
+		authd.APIClient = defaultClientID
 	}
+	if authd.APISecret == "" {
+		authd.APISecret = defaultClientSecret
+	}
+	return authd
+}
 
-	apiclient, orbitclient, diags := authenticatedClients(ctx, authd)
-
-	if apiclient != nil {
-		log.Printf("[INFO] Brightbox Client configured for URL: %s", apiclient.ResourceBaseURL())
+// configureClient checks the configuration and prepares a
+// CompositeClient. No requests are made until a client is first used.
+func configureClient(ctx context.Context, authd authdetails) (*CompositeClient, diag.Diagnostics) {
+	ctx = withLogSubsystems(ctx)
+	tflog.Debug(ctx, "Configuring Brightbox Clients")
+	authd, diags := applyProfile(ctx, authd)
+	if diags.HasError() {
+		return nil, diags
+	}
+	authd, diags = applyRegion(ctx, authd)
+	if diags.HasError() {
+		return nil, diags
 	}
-	if orbitclient != nil {
-		log.Printf("[INFO] Orbit Client configured for URL: %s", orbitclient.ResourceBaseURL())
+	authd = applyDefaults(authd)
+	if err := validateConfig(ctx, authd); err.HasError() {
+		return nil, err
 	}
 
-	composite := &CompositeClient{
-		APIClient:   apiclient,
-		OrbitClient: orbitclient,
+	if authd.Transport.ReadOnly {
+		tokenURL, err := (&endpoint.Config{BaseURL: authd.APIURL}).TokenURL()
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		authd.Transport.TokenURL = tokenURL
+	}
+	httpClient, transportDiags := newHTTPClient(ctx, authd.Transport)
+	diags = append(diags, transportDiags...)
+	if diags.HasError() {
+		return nil, diags
 	}
 
-	return composite, diags
+	return &CompositeClient{
+		authd:      authd,
+		httpClient: httpClient,
+		logCtx:     context.WithoutCancel(ctx),
+	}, diags
 }
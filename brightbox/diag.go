@@ -2,7 +2,6 @@ package brightbox
 
 import (
 	"errors"
-	"log"
 	"strings"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
@@ -16,24 +15,63 @@ func brightboxFromErrSlice(err error) diag.Diagnostics {
 	}
 }
 
+// ErrorSummary returns the summary and detail reported for err, so
+// resources served by the plugin framework describe errors the same way
+func ErrorSummary(err error) (string, string) {
+	result := brightboxFromErr(err)
+	return result.Summary, result.Detail
+}
+
 func brightboxFromErr(err error) diag.Diagnostic {
+	if errors.Is(err, errStorageOnly) {
+		return storageOnlyDiagnostic()
+	}
+	if errors.Is(err, errConfigUnknown) {
+		return configUnknownDiagnostic()
+	}
+	var accountErr *accountError
+	if errors.As(err, &accountErr) {
+		return accountErr.Diagnostic()
+	}
 	var brightboxError *brightbox.APIError
 	if errors.As(err, &brightboxError) {
-		log.Printf("[DEBUG] Returning API Diagnostic")
 		return apiErrorDiagnostic(brightboxError)
 	}
+	var readOnly *readOnlyError
+	if errors.As(err, &readOnly) {
+		return readOnlyDiagnostic(readOnly)
+	}
 	var oauthError *oauth2.RetrieveError
 	if errors.As(err, &oauthError) {
-		log.Printf("[DEBUG] Returning Oauth2 Diagnostic")
 		return oauth2ErrorDiagnostic(oauthError)
 	}
-	log.Printf("[DEBUG] Returning Normal Error with type %T", err)
 	return diag.Diagnostic{
 		Severity: diag.Error,
 		Summary:  err.Error(),
 	}
 }
 
+func storageOnlyDiagnostic() diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "These credentials only grant storage access",
+		Detail: "The provider is configured with an API client in the storage permissions group, " +
+			"which can only manage Orbit resources such as brightbox_orbit_container. " +
+			"Use credentials with full access to manage infrastructure.",
+	}
+}
+
+func configUnknownDiagnostic() diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "The provider configuration depends on values that are not known until apply",
+		Detail: "Resources keep their existing state until the configuration is known, " +
+			"but data sources, imports and new resources need a client. " +
+			"Apply the resources the provider configuration depends on first, for example with -target, " +
+			"or use a version of Terraform that supports deferred actions.",
+	}
+}
+
 func oauth2ErrorDiagnostic(err *oauth2.RetrieveError) diag.Diagnostic {
 	descSlice := []string{}
 	if err.ErrorDescription != "" {
@@ -0,0 +1,107 @@
+package brightbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// credentialProcessTimeout limits how long a credential process
+	// may run
+	credentialProcessTimeout = 1 * time.Minute
+	// credentialProcessEarlyExpiry is how long before its expiry a
+	// token is replaced by running the credential process again
+	credentialProcessEarlyExpiry = 2 * time.Minute
+)
+
+// tokenCredentials authenticates with a token obtained outside the
+// provider rather than with a client secret or password
+type tokenCredentials struct {
+	endpoint.Config
+	source oauth2.TokenSource
+}
+
+// Client implements the brightbox.Oauth2 interface
+func (c *tokenCredentials) Client(ctx context.Context) (*http.Client, oauth2.TokenSource, error) {
+	if _, err := c.source.Token(); err != nil {
+		return nil, nil, err
+	}
+	return oauth2.NewClient(ctx, c.source), c.source, nil
+}
+
+// staticTokenSource uses a pre-issued access token for as long as the
+// API accepts it
+func staticTokenSource(accessToken string) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+	})
+}
+
+// credentialProcessOutput is the JSON a credential process writes to
+// its standard output
+type credentialProcessOutput struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// processTokenSource obtains tokens by running a local command
+type processTokenSource struct {
+	ctx     context.Context
+	command string
+}
+
+// newProcessTokenSource returns a token source that runs command
+// again whenever its last token is close to expiring
+func newProcessTokenSource(ctx context.Context, command string) oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(nil, &processTokenSource{ctx: ctx, command: command}, credentialProcessEarlyExpiry)
+}
+
+func (p *processTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, credentialProcessTimeout)
+	defer cancel()
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Running credential process")
+	cmd := shellCommand(ctx, p.command)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential_process failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var output credentialProcessOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("credential_process returned invalid JSON: %w", err)
+	}
+	if output.AccessToken == "" {
+		return nil, fmt.Errorf("credential_process returned no access_token")
+	}
+	if !output.ExpiresAt.IsZero() && time.Until(output.ExpiresAt) <= 0 {
+		return nil, fmt.Errorf("credential_process returned a token that expired at %s", output.ExpiresAt)
+	}
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Credential process returned a token", map[string]interface{}{
+		"expires_at": output.ExpiresAt.String(),
+	})
+	return &oauth2.Token{
+		AccessToken: output.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      output.ExpiresAt,
+	}, nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
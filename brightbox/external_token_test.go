@@ -0,0 +1,114 @@
+package brightbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"gotest.tools/v3/assert"
+)
+
+// writeCredentialProcess creates a script that prints a token valid
+// for the given duration, counting its runs in a file
+func writeCredentialProcess(t *testing.T, validFor time.Duration) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use a shell script")
+	}
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "broker.sh")
+	expiry := time.Now().Add(validFor).UTC().Format(time.RFC3339)
+	content := fmt.Sprintf(`#!/bin/sh
+echo run >> %q
+runs=$(wc -l < %q | tr -d ' ')
+printf '{"access_token": "broker-%%s", "expires_at": "%s"}' "$runs"
+`, counter, counter, expiry)
+	assert.NilError(t, os.WriteFile(script, []byte(content), 0700))
+	return script, counter
+}
+
+func runCount(t *testing.T, counter string) int {
+	content, err := os.ReadFile(counter)
+	assert.NilError(t, err)
+	return strings.Count(string(content), "run")
+}
+
+func TestProcessTokenSourceReusesToken(t *testing.T) {
+	script, counter := writeCredentialProcess(t, time.Hour)
+	ts := newProcessTokenSource(context.Background(), script)
+	for i := 0; i < 3; i++ {
+		token, err := ts.Token()
+		assert.NilError(t, err)
+		assert.Equal(t, token.AccessToken, "broker-1")
+	}
+	assert.Equal(t, runCount(t, counter), 1)
+}
+
+func TestProcessTokenSourceRefreshesNearExpiry(t *testing.T) {
+	script, counter := writeCredentialProcess(t, credentialProcessEarlyExpiry/2)
+	ts := newProcessTokenSource(context.Background(), script)
+	token, err := ts.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "broker-1")
+	token, err = ts.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "broker-2")
+	assert.Equal(t, runCount(t, counter), 2)
+}
+
+func TestProcessTokenSourceErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential process tests use shell commands")
+	}
+	testCases := []struct {
+		command string
+		err     string
+	}{
+		{"echo broken >&2; exit 3", "credential_process failed: exit status 3: broken"},
+		{"echo not json", "credential_process returned invalid JSON"},
+		{`echo '{"expires_at": "2030-01-01T00:00:00Z"}'`, "credential_process returned no access_token"},
+		{`echo '{"access_token": "x", "expires_at": "2001-01-01T00:00:00Z"}'`, "credential_process returned a token that expired"},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.command, func(t *testing.T) {
+			_, err := newProcessTokenSource(context.Background(), tcase.command).Token()
+			assert.ErrorContains(t, err, tcase.err)
+		})
+	}
+}
+
+func TestAccessTokenCredentials(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	authd := authdetails{APIURL: server.URL, accessToken: "pre-issued"}
+	assert.Assert(t, !validateConfig(context.Background(), applyDefaults(authd)).HasError())
+	conf, err := confFromAuthd(context.Background(), authd)
+	assert.NilError(t, err)
+	client, err := brightbox.Connect(context.Background(), conf)
+	assert.NilError(t, err)
+	_, err = client.Servers(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, authorization, "Bearer pre-issued")
+}
+
+func TestExternalTokenValidation(t *testing.T) {
+	diags := validateConfig(context.Background(), authdetails{accessToken: "token", CredentialProcess: "broker"})
+	assert.Equal(t, diags[0].Summary, "Only one of access_token and credential_process can be set")
+
+	diags = validateConfig(context.Background(), authdetails{CredentialProcess: "broker", UserName: "fred"})
+	assert.Equal(t, diags[0].Summary, "User Credentials should be blank with access_token or credential_process")
+}
@@ -0,0 +1,99 @@
+package brightbox
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// idIdentity is the identity of resources known by their object ID
+// alone
+func idIdentity() *schema.ResourceIdentity {
+	return stringIdentity("id", "The ID of the object")
+}
+
+// nameIdentity is the identity of resources known by a name, which is
+// also their ID
+func nameIdentity() *schema.ResourceIdentity {
+	return stringIdentity("name", "The name of the object")
+}
+
+func stringIdentity(attribute string, description string) *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				attribute: {
+					Description:       description,
+					Type:              schema.TypeString,
+					RequiredForImport: true,
+				},
+			}
+		},
+	}
+}
+
+// idImporter imports resources by object ID, given either as the
+// import ID or as the identity
+func idImporter() *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: schema.ImportStatePassthroughWithIdentity("id"),
+	}
+}
+
+// nameImporter imports resources by name, given either as the import
+// ID or as the identity
+func nameImporter() *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: schema.ImportStatePassthroughWithIdentity("name"),
+	}
+}
+
+// setIDIdentity records the object ID as the resource identity
+func setIDIdentity(d *schema.ResourceData) diag.Diagnostics {
+	return setIdentity(d, map[string]interface{}{"id": d.Id()})
+}
+
+// setNameIdentity records the ID, which is the object name, as the
+// resource identity
+func setNameIdentity(d *schema.ResourceData) diag.Diagnostics {
+	return setIdentity(d, map[string]interface{}{"name": d.Id()})
+}
+
+func setIdentity(d *schema.ResourceData, values map[string]interface{}) diag.Diagnostics {
+	identity, err := d.Identity()
+	if err != nil {
+		return diag.Errorf("unexpected: %s", err)
+	}
+	var diags diag.Diagnostics
+	for key, value := range values {
+		if err := identity.Set(key, value); err != nil {
+			diags = append(diags, diag.Errorf("unexpected: %s", err)...)
+		}
+	}
+	return diags
+}
+
+// setIdentityFromState records the identity from the attributes
+// already held in state, for reads that do not reach the API. State
+// written before identities existed has none to carry forward.
+func setIdentityFromState(d *schema.ResourceData, identity *schema.ResourceIdentity) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	values := make(map[string]interface{})
+	for key := range identity.SchemaFunc() {
+		if key == "id" {
+			values[key] = d.Id()
+			continue
+		}
+		value := d.Get(key)
+		if set, ok := value.(*schema.Set); ok {
+			list := expandStringValueList(set.List())
+			sort.Strings(list)
+			value = list
+		}
+		values[key] = value
+	}
+	return setIdentity(d, values)
+}
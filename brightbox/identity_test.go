@@ -0,0 +1,129 @@
+package brightbox
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+)
+
+func TestImportableResourcesHaveIdentity(t *testing.T) {
+	for name, resource := range Provider("test").ResourcesMap {
+		if resource.Importer == nil {
+			continue
+		}
+		assert.Assert(t, resource.Identity != nil, name)
+		assert.NilError(t, resource.Identity.InternalIdentityValidate(), name)
+	}
+}
+
+func TestServerGroupMembershipImport(t *testing.T) {
+	testCases := map[string]*terraform.InstanceState{
+		"import ID": {
+			ID: "grp-12345/srv-bbbbb/srv-aaaaa",
+		},
+		"identity": {
+			Identity: map[string]string{
+				"group":     "grp-12345",
+				"servers.#": "2",
+				"servers.0": "srv-bbbbb",
+				"servers.1": "srv-aaaaa",
+			},
+		},
+	}
+	ids := map[string]string{}
+	for name, state := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := resourceBrightboxServerGroupMembership().Data(state)
+			result, err := resourceBrightboxServerGroupMembershipImport(context.Background(), d, nil)
+			assert.NilError(t, err)
+			assert.Equal(t, len(result), 1)
+			assert.Equal(t, result[0].Id(), "grp-12345/srv-aaaaa/srv-bbbbb")
+			ids[name] = result[0].Id()
+			assert.Equal(t, result[0].Get("group"), "grp-12345")
+			servers := sliceFromStringSet(result[0], "servers")
+			sort.Strings(servers)
+			assert.DeepEqual(t, servers, []string{"srv-aaaaa", "srv-bbbbb"})
+		})
+	}
+	assert.Equal(t, ids["import ID"], ids["identity"])
+}
+
+func TestServerGroupMembershipImportInvalid(t *testing.T) {
+	testCases := map[string]*terraform.InstanceState{
+		"import ID": {
+			ID: "grp-12345",
+		},
+		"identity": {
+			Identity: map[string]string{
+				"group":     "grp-12345",
+				"servers.#": "0",
+			},
+		},
+	}
+	for name, state := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := resourceBrightboxServerGroupMembership().Data(state)
+			_, err := resourceBrightboxServerGroupMembershipImport(context.Background(), d, nil)
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func TestSetServerGroupMembershipAttributes(t *testing.T) {
+	d := resourceBrightboxServerGroupMembership().Data(&terraform.InstanceState{
+		ID: "grp-12345/srv-aaaaa/srv-ccccc",
+		Attributes: map[string]string{
+			"group":     "grp-12345",
+			"servers.#": "2",
+			"servers.0": "srv-ccccc",
+			"servers.1": "srv-aaaaa",
+		},
+	})
+	diags := setServerGroupMembershipAttributes(d, &brightbox.ServerGroup{
+		ID: "grp-12345",
+		Servers: []brightbox.Server{
+			{ID: "srv-ccccc"},
+			{ID: "srv-bbbbb"},
+			{ID: "srv-aaaaa"},
+		},
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	assert.Equal(t, d.Id(), "grp-12345/srv-aaaaa/srv-ccccc")
+
+	identity, err := d.Identity()
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Get("group"), "grp-12345")
+	assert.DeepEqual(t, identity.Get("servers"), []interface{}{"srv-aaaaa", "srv-ccccc"})
+}
+
+func TestSetNameIdentity(t *testing.T) {
+	d := resourceBrightboxContainer().Data(&terraform.InstanceState{ID: "backups"})
+	diags := setNameIdentity(d)
+	assert.Assert(t, !diags.HasError(), diags)
+	identity, err := d.Identity()
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Get("name"), "backups")
+}
+
+func TestSetIdentityFromState(t *testing.T) {
+	resource := resourceBrightboxServerGroupMembership()
+	d := resource.Data(&terraform.InstanceState{
+		ID: "grp-12345/srv-aaaaa/srv-ccccc",
+		Attributes: map[string]string{
+			"group":     "grp-12345",
+			"servers.#": "2",
+			"servers.0": "srv-ccccc",
+			"servers.1": "srv-aaaaa",
+		},
+	})
+	diags := setIdentityFromState(d, resource.Identity)
+	assert.Assert(t, !diags.HasError(), diags)
+	identity, err := d.Identity()
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Get("group"), "grp-12345")
+	assert.DeepEqual(t, identity.Get("servers"), []interface{}{"srv-aaaaa", "srv-ccccc"})
+}
@@ -0,0 +1,175 @@
+package brightbox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ListFilter selects the objects returned by ListObjects. Name is a
+// regular expression, as in the data sources; Zone and Status must
+// match exactly. Empty fields match everything.
+type ListFilter struct {
+	Name   string
+	Zone   string
+	Status string
+}
+
+// ListedObject is an object found by ListObjects
+type ListedObject struct {
+	ID   string
+	Name string
+
+	state func() (cty.Value, diag.Diagnostics)
+}
+
+// State returns the state the managed resource records for the
+// object, as a value of the resource schema type
+func (o ListedObject) State() (cty.Value, diag.Diagnostics) {
+	return o.state()
+}
+
+// listedFields are the fields of an object ListFilter matches against
+type listedFields struct {
+	id     string
+	name   string
+	zones  []string
+	status string
+}
+
+type objectLister func(context.Context, *brightbox.Client, ListFilter) ([]ListedObject, error)
+
+// objectListers are the managed resource types ListObjects supports
+var objectListers = map[string]objectLister{
+	"brightbox_cloudip": listObjects(
+		(*brightbox.Client).CloudIPs,
+		"Cloud IP",
+		resourceBrightboxCloudIP,
+		setCloudIPAttributes,
+		func(obj *brightbox.CloudIP) listedFields {
+			return listedFields{id: obj.ID, name: obj.Name, status: obj.Status.String()}
+		},
+		func(_ *brightbox.CloudIP) bool { return false },
+	),
+	"brightbox_firewall_policy": listObjects(
+		(*brightbox.Client).FirewallPolicies,
+		"Firewall Policy",
+		resourceBrightboxFirewallPolicy,
+		setFirewallPolicyAttributes,
+		func(obj *brightbox.FirewallPolicy) listedFields {
+			return listedFields{id: obj.ID, name: obj.Name}
+		},
+		func(_ *brightbox.FirewallPolicy) bool { return false },
+	),
+	"brightbox_load_balancer": listObjects(
+		(*brightbox.Client).LoadBalancers,
+		"Load Balancer",
+		resourceBrightboxLoadBalancer,
+		setLoadBalancerAttributes,
+		func(obj *brightbox.LoadBalancer) listedFields {
+			return listedFields{id: obj.ID, name: obj.Name, status: obj.Status.String()}
+		},
+		loadBalancerUnavailable,
+	),
+	"brightbox_server": listObjects(
+		(*brightbox.Client).Servers,
+		"Server",
+		resourceBrightboxServer,
+		setServerAttributes,
+		func(obj *brightbox.Server) listedFields {
+			fields := listedFields{id: obj.ID, name: obj.Name, status: obj.Status.String()}
+			if obj.Zone != nil {
+				fields.zones = []string{obj.Zone.ID, obj.Zone.Handle}
+			}
+			return fields
+		},
+		serverUnavailable,
+	),
+	"brightbox_volume": listObjects(
+		(*brightbox.Client).Volumes,
+		"Volume",
+		resourceBrightboxVolume,
+		setVolumeAttributes,
+		func(obj *brightbox.Volume) listedFields {
+			return listedFields{id: obj.ID, name: obj.Name, status: obj.Status.String()}
+		},
+		volumeUnavailable,
+	),
+}
+
+// ListObjects returns the objects of the managed resource type
+// typeName that match filter
+func ListObjects(ctx context.Context, client *CompositeClient, typeName string, filter ListFilter) ([]ListedObject, error) {
+	lister, ok := objectListers[typeName]
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be listed", typeName)
+	}
+	apiClient, err := client.APIClient()
+	if err != nil {
+		return nil, err
+	}
+	return lister(ctx, apiClient, filter)
+}
+
+func listObjects[O any](
+	reader func(*brightbox.Client, context.Context) ([]O, error),
+	objectName string,
+	resource func() *schema.Resource,
+	setter func(*schema.ResourceData, *O) diag.Diagnostics,
+	fields func(*O) listedFields,
+	missing func(*O) bool,
+) objectLister {
+	return func(ctx context.Context, client *brightbox.Client, filter ListFilter) ([]ListedObject, error) {
+		ctx = NewLogContext(ctx, objectName, "", "list")
+		var nameRe *regexp.Regexp
+		if filter.Name != "" {
+			var err error
+			if nameRe, err = regexp.Compile(filter.Name); err != nil {
+				return nil, err
+			}
+		}
+
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" list called. Retrieving object list")
+		objects, err := reader(client, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		r := resource()
+		results := make([]ListedObject, 0, len(objects))
+		for i := range objects {
+			object := &objects[i]
+			found := fields(object)
+			if missing(object) ||
+				(nameRe != nil && !nameRe.MatchString(found.name)) ||
+				(filter.Zone != "" && !slices.Contains(found.zones, filter.Zone)) ||
+				(filter.Status != "" && filter.Status != found.status) {
+				continue
+			}
+			results = append(results, ListedObject{
+				ID:   found.id,
+				Name: found.name,
+				state: func() (cty.Value, diag.Diagnostics) {
+					d := r.Data(nil)
+					if diags := setter(d, object); diags.HasError() {
+						return cty.NilVal, diags
+					}
+					value, err := d.State().AttrsAsObjectValue(r.CoreConfigSchema().ImpliedType())
+					if err != nil {
+						return cty.NilVal, diag.FromErr(err)
+					}
+					return value, nil
+				},
+			})
+		}
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, fmt.Sprintf("%d %s objects found", len(results), objectName))
+		return results, nil
+	}
+}
@@ -0,0 +1,103 @@
+package brightbox
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"gotest.tools/v3/assert"
+)
+
+const testServerList = `[
+	{"id":"srv-aaaaa","name":"web-1","status":"active","zone":{"id":"zon-aaaaa","handle":"gb1-a"},
+	 "image":{"id":"img-aaaaa","username":"ubuntu"},"server_type":{"id":"typ-aaaaa","handle":"1gb.ssd"}},
+	{"id":"srv-bbbbb","name":"web-2","status":"inactive","zone":{"id":"zon-bbbbb","handle":"gb1-b"},
+	 "image":{"id":"img-aaaaa","username":"ubuntu"},"server_type":{"id":"typ-aaaaa","handle":"1gb.ssd"}},
+	{"id":"srv-ccccc","name":"db-1","status":"active","zone":{"id":"zon-aaaaa","handle":"gb1-a"},
+	 "image":{"id":"img-aaaaa","username":"ubuntu"},"server_type":{"id":"typ-aaaaa","handle":"1gb.ssd"}},
+	{"id":"srv-ddddd","name":"web-3","status":"deleted","zone":{"id":"zon-aaaaa","handle":"gb1-a"},
+	 "image":{"id":"img-aaaaa","username":"ubuntu"},"server_type":{"id":"typ-aaaaa","handle":"1gb.ssd"}}
+]`
+
+// newFakeAPIClient returns a client configured against a server that
+// issues tokens, serves the account and passes every other request to
+// api
+func newFakeAPIClient(t *testing.T, api http.HandlerFunc) *CompositeClient {
+	server := newFakeTokenServer(t)
+	var apiRequests int32
+	server.Config.Handler = countingHandler(server.Config.Handler, &apiRequests, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/1.0/accounts/acc-12345" {
+			w.Write([]byte(`{"id":"acc-12345","name":"Example","status":"active"}`))
+			return
+		}
+		api(w, r)
+	})
+	composite, diags := configureClient(context.Background(), authdetails{
+		APIClient: "cli-12345",
+		APISecret: "secret",
+		Account:   "acc-12345",
+		APIURL:    server.URL,
+		OrbitURL:  server.URL + "/",
+	})
+	assert.Assert(t, !diags.HasError(), diags)
+	return composite
+}
+
+func newServerListClient(t *testing.T) *CompositeClient {
+	return newFakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.0/servers" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testServerList))
+	})
+}
+
+func TestListObjects(t *testing.T) {
+	client := newServerListClient(t)
+	testCases := map[string]struct {
+		filter   ListFilter
+		expected []string
+	}{
+		"everything but deleted": {ListFilter{}, []string{"srv-aaaaa", "srv-bbbbb", "srv-ccccc"}},
+		"name":                   {ListFilter{Name: "^web-"}, []string{"srv-aaaaa", "srv-bbbbb"}},
+		"zone handle":            {ListFilter{Zone: "gb1-a"}, []string{"srv-aaaaa", "srv-ccccc"}},
+		"zone id":                {ListFilter{Zone: "zon-bbbbb"}, []string{"srv-bbbbb"}},
+		"status":                 {ListFilter{Name: "web", Status: "active"}, []string{"srv-aaaaa"}},
+		"nothing":                {ListFilter{Status: "failed"}, []string{}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			objects, err := ListObjects(context.Background(), client, "brightbox_server", tc.filter)
+			assert.NilError(t, err)
+			ids := make([]string, 0, len(objects))
+			for _, object := range objects {
+				ids = append(ids, object.ID)
+			}
+			assert.DeepEqual(t, ids, tc.expected)
+		})
+	}
+}
+
+func TestListObjectsState(t *testing.T) {
+	objects, err := ListObjects(context.Background(), newServerListClient(t), "brightbox_server", ListFilter{Name: "^db-1$"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 1)
+	assert.Equal(t, objects[0].Name, "db-1")
+
+	state, diags := objects[0].State()
+	assert.Assert(t, !diags.HasError(), diags)
+	assert.Assert(t, state.GetAttr("id").RawEquals(cty.StringVal("srv-ccccc")))
+	assert.Assert(t, state.GetAttr("zone").RawEquals(cty.StringVal("gb1-a")))
+	assert.Assert(t, state.GetAttr("username").RawEquals(cty.StringVal("ubuntu")))
+}
+
+func TestListObjectsErrors(t *testing.T) {
+	client := newServerListClient(t)
+	_, err := ListObjects(context.Background(), client, "brightbox_server_group", ListFilter{})
+	assert.ErrorContains(t, err, "cannot be listed")
+	_, err = ListObjects(context.Background(), client, "brightbox_server", ListFilter{Name: "("})
+	assert.ErrorContains(t, err, "missing closing")
+}
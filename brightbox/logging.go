@@ -0,0 +1,272 @@
+package brightbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Logging subsystems. Their levels can be set individually with
+// TF_LOG_PROVIDER_BRIGHTBOX_API, TF_LOG_PROVIDER_BRIGHTBOX_ORBIT and
+// TF_LOG_PROVIDER_BRIGHTBOX_AUTH.
+const (
+	logSubsystemAPI   = "api"
+	logSubsystemOrbit = "orbit"
+	logSubsystemAuth  = "auth"
+)
+
+// Structured logging field keys
+const (
+	logFieldResourceType = "resource_type"
+	logFieldObjectID     = "object_id"
+	logFieldOperation    = "operation"
+	logFieldOptions      = "options"
+	logFieldObject       = "object"
+)
+
+const (
+	logLevelEnvVar     = "TF_LOG_PROVIDER_BRIGHTBOX"
+	logMaskReplacement = "***"
+)
+
+var logSubsystems = []string{logSubsystemAPI, logSubsystemOrbit, logSubsystemAuth}
+
+// sensitiveKeyRegexp matches keys, once normalised, whose values must
+// never be logged
+var sensitiveKeyRegexp = regexp.MustCompile(
+	"password|secret|token|authorization|userdata|privatekey|synckey|tempurlkey|cookie",
+)
+
+// logMaskRegexes catch credentials that end up in free text, such as
+// error messages, whatever subsystem they are logged to
+var logMaskRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`(?i)(password|secret|access_token|refresh_token)=[^&\s"]+`),
+}
+
+// NewLogContext sets up the provider logging subsystems on ctx, adding
+// the object and operation being worked on to every entry. Empty
+// values are left out.
+func NewLogContext(ctx context.Context, resourceType string, id string, operation string) context.Context {
+	for key, value := range map[string]string{
+		logFieldResourceType: resourceType,
+		logFieldObjectID:     id,
+		logFieldOperation:    operation,
+	} {
+		if value != "" {
+			ctx = tflog.SetField(ctx, key, value)
+		}
+	}
+	return withLogSubsystems(ctx)
+}
+
+// withLogSubsystems creates the provider logging subsystems, each
+// masking sensitive values
+func withLogSubsystems(ctx context.Context) context.Context {
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, logMaskRegexes...)
+	ctx = tflog.MaskMessageRegexes(ctx, logMaskRegexes...)
+	for _, subsystem := range logSubsystems {
+		ctx = tflog.NewSubsystem(ctx, subsystem,
+			tflog.WithRootFields(),
+			tflog.WithLevelFromEnv(logLevelEnvVar, subsystem),
+		)
+		ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, subsystem, logMaskRegexes...)
+		ctx = tflog.SubsystemMaskMessageRegexes(ctx, subsystem, logMaskRegexes...)
+	}
+	return ctx
+}
+
+// isSensitiveLogKey reports whether a field, header or attribute name
+// holds a value that must be masked
+func isSensitiveLogKey(key string) bool {
+	normalised := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+	return sensitiveKeyRegexp.MatchString(normalised)
+}
+
+// redacted converts v, typically an options struct, into plain maps
+// and slices with sensitive values masked, ready to log as a field
+func redacted(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%T", v)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Sprintf("%T", v)
+	}
+	return redactValue(generic)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if isSensitiveLogKey(key) && item != nil && item != "" {
+				value[key] = logMaskReplacement
+			} else {
+				value[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+// redactHeaders returns the headers as log fields with credentials
+// masked
+func redactHeaders(header http.Header) map[string]interface{} {
+	fields := make(map[string]interface{}, len(header))
+	for key, values := range header {
+		switch {
+		case isSensitiveLogKey(key):
+			fields[key] = logMaskReplacement
+		case len(values) == 1:
+			fields[key] = values[0]
+		default:
+			fields[key] = values
+		}
+	}
+	return fields
+}
+
+// redactBody masks sensitive values in JSON and form encoded bodies.
+// Other content is returned unchanged.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return logMaskReplacement
+		}
+		for key := range values {
+			if isSensitiveLogKey(key) {
+				values.Set(key, logMaskReplacement)
+			}
+		}
+		return values.Encode()
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var generic interface{}
+		if err := json.Unmarshal(body, &generic); err != nil {
+			return logMaskReplacement
+		}
+		data, err := json.Marshal(redactValue(generic))
+		if err != nil {
+			return logMaskReplacement
+		}
+		return string(data)
+	}
+	return string(body)
+}
+
+// httpTraceEnabled reports whether any of the Terraform log level
+// variables that reach the provider is set to DEBUG or TRACE, so
+// bodies are only copied when they could be logged
+func httpTraceEnabled() bool {
+	names := []string{"TF_LOG", "TF_LOG_PROVIDER", logLevelEnvVar}
+	for _, subsystem := range logSubsystems {
+		names = append(names, logLevelEnvVar+"_"+strings.ToUpper(subsystem))
+	}
+	for _, name := range names {
+		switch strings.ToUpper(os.Getenv(name)) {
+		case "DEBUG", "TRACE":
+			return true
+		}
+	}
+	return false
+}
+
+// requestLogSubsystem picks the subsystem for a request sent through
+// the shared HTTP client
+func requestLogSubsystem(req *http.Request) string {
+	if req.Header.Get("X-Auth-Token") != "" {
+		return logSubsystemOrbit
+	}
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		return logSubsystemAuth
+	}
+	return logSubsystemAPI
+}
+
+// logTransport traces requests and responses to the api, orbit or
+// auth subsystem with credentials and sensitive attributes masked
+type logTransport struct {
+	base http.RoundTripper
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	subsystem := requestLogSubsystem(req)
+	body, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	tflog.SubsystemDebug(ctx, subsystem, "Sending HTTP request", map[string]interface{}{
+		"http_method":  req.Method,
+		"http_url":     req.URL.Redacted(),
+		"http_headers": redactHeaders(req.Header),
+		"http_body":    redactBody(req.Header.Get("Content-Type"), body),
+	})
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, subsystem, "HTTP request failed", map[string]interface{}{
+			"http_method": req.Method,
+			"http_url":    req.URL.Redacted(),
+			"error":       err.Error(),
+		})
+		return resp, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return nil, err
+	}
+	tflog.SubsystemDebug(ctx, subsystem, "Received HTTP response", map[string]interface{}{
+		"http_method":      req.Method,
+		"http_url":         req.URL.Redacted(),
+		"http_status_code": resp.StatusCode,
+		"http_headers":     redactHeaders(resp.Header),
+		"http_body":        redactBody(resp.Header.Get("Content-Type"), respBody),
+	})
+	return resp, nil
+}
+
+// peekRequestBody returns a copy of the request body, leaving the
+// body in place to be sent
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
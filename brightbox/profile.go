@@ -0,0 +1,129 @@
+package brightbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	configDirName  = ".brightbox"
+	configFileName = "config"
+)
+
+// Brightbox CLI configuration keys and the authdetails fields they
+// populate
+var profileKeys = []struct {
+	key    string
+	target func(*authdetails) *string
+}{
+	{"client_id", func(a *authdetails) *string { return &a.APIClient }},
+	{"secret", func(a *authdetails) *string { return &a.APISecret }},
+	{"default_account", func(a *authdetails) *string { return &a.Account }},
+	{"api_url", func(a *authdetails) *string { return &a.APIURL }},
+	{"orbit_url", func(a *authdetails) *string { return &a.OrbitURL }},
+}
+
+// brightboxConfigPath returns the location of the Brightbox CLI
+// configuration file
+func brightboxConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configDirName, configFileName), nil
+}
+
+// applyProfile fills in any details not already set from the named
+// section of the Brightbox CLI configuration file. Explicit settings
+// always take precedence over the file.
+func applyProfile(ctx context.Context, authd authdetails) (authdetails, diag.Diagnostics) {
+	if authd.Profile == "" {
+		return authd, nil
+	}
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Loading profile", map[string]interface{}{
+		"profile": authd.Profile,
+	})
+	path, err := brightboxConfigPath()
+	if err != nil {
+		return authd, diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to locate Brightbox config file for profile %q", authd.Profile),
+				Detail:   err.Error(),
+			},
+		}
+	}
+	section, diags := loadProfileSection(path, authd.Profile)
+	if diags.HasError() {
+		return authd, diags
+	}
+	for _, v := range profileKeys {
+		target := v.target(&authd)
+		if *target == "" {
+			*target = section.Key(v.key).String()
+		}
+	}
+	tflog.SubsystemDebug(ctx, logSubsystemAuth, "Profile loaded", map[string]interface{}{
+		"profile": authd.Profile,
+		"path":    path,
+	})
+	return authd, nil
+}
+
+func loadProfileSection(path string, profile string) (*ini.Section, diag.Diagnostics) {
+	cfg, err := ini.Load(path)
+	if err != nil {
+		summary := fmt.Sprintf("Unable to read Brightbox config file %s", path)
+		if errors.Is(err, os.ErrNotExist) {
+			summary = fmt.Sprintf("Brightbox config file %s not found", path)
+		}
+		return nil, diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  summary,
+				Detail:   fmt.Sprintf("Profile %q could not be loaded: %s", profile, err),
+			},
+		}
+	}
+	section, err := cfg.GetSection(profile)
+	if err != nil {
+		return nil, diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Profile %q not found in %s", profile, path),
+				Detail:   fmt.Sprintf("Available profiles: %s", strings.Join(profileNames(cfg), ", ")),
+			},
+		}
+	}
+	if section.Key("client_id").String() == "" {
+		return nil, diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Profile %q in %s is malformed", profile, path),
+				Detail:   "The profile has no client_id entry",
+			},
+		}
+	}
+	return section, nil
+}
+
+// profileNames lists the client sections in the config file, skipping
+// the default and core sections which hold no credentials
+func profileNames(cfg *ini.File) []string {
+	var result []string
+	for _, name := range cfg.SectionStrings() {
+		if name == ini.DefaultSection || name == "core" {
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package brightbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testProfileConfig = `[core]
+default_client = cli-prof1
+
+[cli-prof1]
+client_id = cli-prof1
+secret = profsecret
+default_account = acc-prof1
+api_url = https://api.gb1s.brightbox.com
+orbit_url = https://orbit.gb1s.brightbox.com
+
+[broken]
+secret = nothing
+`
+
+func writeTestProfileConfig(t *testing.T, content string) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if content == "" {
+		return
+	}
+	dir := filepath.Join(home, configDirName)
+	assert.NilError(t, os.Mkdir(dir, 0700))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, configFileName), []byte(content), 0600))
+}
+
+func TestApplyProfile(t *testing.T) {
+	writeTestProfileConfig(t, testProfileConfig)
+	authd, diags := applyProfile(context.Background(), authdetails{Profile: "cli-prof1"})
+	assert.Assert(t, !diags.HasError())
+	assert.Equal(t, "cli-prof1", authd.APIClient)
+	assert.Equal(t, "profsecret", authd.APISecret)
+	assert.Equal(t, "acc-prof1", authd.Account)
+	assert.Equal(t, "https://api.gb1s.brightbox.com", authd.APIURL)
+	assert.Equal(t, "https://orbit.gb1s.brightbox.com", authd.OrbitURL)
+}
+
+func TestApplyProfileExplicitOverrides(t *testing.T) {
+	writeTestProfileConfig(t, testProfileConfig)
+	authd, diags := applyProfile(context.Background(), authdetails{
+		Profile: "cli-prof1",
+		Account: "acc-other",
+		APIURL:  "https://api.example.com",
+	})
+	assert.Assert(t, !diags.HasError())
+	assert.Equal(t, "cli-prof1", authd.APIClient)
+	assert.Equal(t, "acc-other", authd.Account)
+	assert.Equal(t, "https://api.example.com", authd.APIURL)
+}
+
+func TestApplyProfileErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		profile string
+		summary string
+	}{
+		{"missing file", "", "cli-prof1", "Brightbox config file %s not found"},
+		{"missing profile", testProfileConfig, "cli-nope1", `Profile "cli-nope1" not found in %s`},
+		{"no client id", testProfileConfig, "broken", `Profile "broken" in %s is malformed`},
+		{"unparseable", "[cli-prof1\nclient_id", "cli-prof1", "Unable to read Brightbox config file %s"},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			writeTestProfileConfig(t, tcase.content)
+			path, err := brightboxConfigPath()
+			assert.NilError(t, err)
+			_, diags := applyProfile(context.Background(), authdetails{Profile: tcase.profile})
+			assert.Assert(t, diags.HasError())
+			assert.Equal(t, diags[0].Summary, fmt.Sprintf(tcase.summary, path))
+		})
+	}
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"time"
 
-	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -15,39 +16,135 @@ const (
 	checkDelay         = 10 * time.Second
 )
 
-// Provider is the Brightbox Terraform driver root
-func Provider() *schema.Provider {
-	return &schema.Provider{
+// Provider is the Brightbox Terraform driver root. The version is
+// reported in the User-Agent of every request the provider makes.
+func Provider(version string) *schema.Provider {
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			"access_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc(accessTokenEnvVar, nil),
+				ConflictsWith: []string{"credential_process"},
+				Description:   "Pre-issued Brightbox Cloud access token, used instead of client or user credentials",
+			},
 			"account": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc(accountEnvVar, nil),
-				Description: "Brightbox Cloud Account to operate upon",
+				Description: "Brightbox Cloud Account ID or name to operate upon",
+			},
+			"allowed_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringMatch(accountRegexp, "must be a valid account ID")},
+				ConflictsWith: []string{"forbidden_account_ids"},
+				Description:   "Account IDs the provider may operate upon. Any other account fails on first use, before resources are read",
 			},
 			"apiclient": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(clientEnvVar, defaultClientID),
+				DefaultFunc: schema.EnvDefaultFunc(clientEnvVar, nil),
 				Description: "Brightbox Cloud API Client/OAuth Application ID",
 			},
 			"apisecret": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(clientSecretEnvVar, defaultClientSecret),
+				DefaultFunc: schema.EnvDefaultFunc(clientSecretEnvVar, nil),
 				Description: "Brightbox Cloud API Client/OAuth Application Secret",
 			},
 			"apiurl": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(apiURLEnvVar, endpoint.DefaultBaseURL),
-				Description: "Brightbox Cloud Api URL for selected Region",
+				DefaultFunc: schema.EnvDefaultFunc(apiURLEnvVar, nil),
+				Description: "Brightbox Cloud Api URL. Overrides the URL for the selected Region",
+			},
+			"ca_bundle": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(caBundleEnvVar, nil),
+				Description: "PEM encoded CA certificates, or the path to a file containing them, to trust in addition to the system roots",
+			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(credentialProcessEnvVar, nil),
+				Description: "Command that prints a JSON access token, run whenever a new token is needed",
+			},
+			"default_create_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultTimeout.String(),
+				ValidateFunc: validateTimeout,
+				Description:  "Create timeout used by resources without a timeouts block of their own",
+			},
+			"default_delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultTimeout.String(),
+				ValidateFunc: validateTimeout,
+				Description:  "Delete timeout used by resources without a timeouts block of their own",
+			},
+			"default_update_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultTimeout.String(),
+				ValidateFunc: validateTimeout,
+				Description:  "Update timeout used by resources without a timeouts block of their own",
+			},
+			"default_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(zoneRegexp, "must be a valid zone ID or handle"),
+				Description:  "Zone used by servers and database servers that don't specify one",
+			},
+			"forbidden_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringMatch(accountRegexp, "must be a valid account ID")},
+				ConflictsWith: []string{"allowed_account_ids"},
+				Description:   "Account IDs the provider must never operate upon",
+			},
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc(httpProxyEnvVar, nil),
+				Description: "URL of the proxy to send all Brightbox requests through. Overrides the standard proxy environment variables",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disable TLS certificate verification. For testing only",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of API requests in flight at once. Zero means no limit",
+			},
+			"max_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum rate of API requests. Zero means no limit",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of times to retry throttled or transiently failing API requests",
 			},
 			"orbit_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(orbitURLEnvVar, endpoint.DefaultOrbitBaseURL),
-				Description: "Brightbox Cloud Orbit URL for selected Region",
+				DefaultFunc: schema.EnvDefaultFunc(orbitURLEnvVar, nil),
+				Description: "Brightbox Cloud Orbit URL. Overrides the URL for the selected Region",
 			},
 			"password": {
 				Type:        schema.TypeString,
@@ -56,6 +153,56 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc(passwordEnvVar, nil),
 				Description: "Brightbox Cloud Password for User Name",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(profileEnvVar, nil),
+				Description: "Brightbox CLI configuration profile to read credentials from",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse every request that would change infrastructure, allowing only reads",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(regionEnvVar, nil),
+				ValidateFunc: validation.StringInSlice(regionNames(), false),
+				Description:  "Brightbox Cloud Region. Sets both the Api and Orbit URLs",
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRetryMaxWait,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of seconds to wait between retries",
+			},
+			"token_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(tokenCacheEnvVar, false),
+				Description: "Cache OAuth tokens on disk and reuse them between runs",
+			},
+			"token_cache_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(tokenCacheFileEnvVar, nil),
+				Description: "Location of the OAuth token cache. Defaults to ~/.brightbox/" + tokenCacheFileName,
+			},
+			"use_first_account": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use the first accessible account when no account is given and the credentials can reach several",
+			},
+			"user_agent_suffix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(userAgentSuffixEnvVar, nil),
+				Description: "Text appended to the User-Agent sent with every request, e.g. to identify a pipeline",
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -81,15 +228,46 @@ func Provider() *schema.Provider {
 			"brightbox_database_server":         resourceBrightboxDatabaseServer(),
 			"brightbox_orbit_container":         resourceBrightboxContainer(),
 			"brightbox_api_client":              resourceBrightboxAPIClient(),
-			"brightbox_config_map":              resourceBrightboxConfigMap(),
 			"brightbox_volume":                  resourceBrightboxVolume(),
 		},
-		ConfigureContextFunc: providerConfigure,
+	}
+	for _, resource := range provider.ResourcesMap {
+		resource.ReadContext = keepStateWhileConfigUnknown(resource.ReadContext, resource.Identity)
+		applyResourceTimeouts(resource)
+	}
+	provider.ConfigureProvider = func(ctx context.Context, req schema.ConfigureProviderRequest, resp *schema.ConfigureProviderResponse) {
+		d := req.ResourceData
+		if !d.GetRawConfig().IsWhollyKnown() {
+			tflog.Warn(ctx, "Provider configuration is not yet known, deferring client setup")
+			resp.Meta = unconfiguredClient()
+			if req.DeferralAllowed {
+				resp.Deferred = &schema.Deferred{Reason: schema.DeferredReasonProviderConfigUnknown}
+			}
+			return
+		}
+		resp.Meta, resp.Diagnostics = providerConfigure(ctx, d, userAgent(version, provider.TerraformVersion, d.Get("user_agent_suffix").(string)))
+	}
+	return provider
+}
+
+// keepStateWhileConfigUnknown skips refreshing a resource while the
+// provider configuration is unknown, so Terraform can still plan
+// against the prior state. Terraform configures the provider again
+// before apply, when everything that needs a client runs.
+func keepStateWhileConfigUnknown(read schema.ReadContextFunc, identity *schema.ResourceIdentity) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if composite, ok := meta.(*CompositeClient); ok && !composite.ConfigKnown() {
+			tflog.Warn(ctx, "Provider configuration is not yet known, keeping the prior state", map[string]interface{}{
+				"id": d.Id(),
+			})
+			return setIdentityFromState(d, identity)
+		}
+		return read(ctx, d, meta)
 	}
 }
 
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	return configureClient(
+func providerConfigure(ctx context.Context, d *schema.ResourceData, userAgent string) (*CompositeClient, diag.Diagnostics) {
+	client, diags := configureClient(
 		ctx,
 		authdetails{
 			APIClient: d.Get("apiclient").(string),
@@ -99,6 +277,42 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 			Account:   d.Get("account").(string),
 			APIURL:    d.Get("apiurl").(string),
 			OrbitURL:  d.Get("orbit_url").(string),
+			Profile:   d.Get("profile").(string),
+			Region:    d.Get("region").(string),
+
+			UseFirstAccount:     d.Get("use_first_account").(bool),
+			AllowedAccountIDs:   sliceFromStringSet(d, "allowed_account_ids"),
+			ForbiddenAccountIDs: sliceFromStringSet(d, "forbidden_account_ids"),
+
+			TokenCache:     d.Get("token_cache").(bool),
+			TokenCacheFile: d.Get("token_cache_file").(string),
+
+			accessToken:       d.Get("access_token").(string),
+			CredentialProcess: d.Get("credential_process").(string),
+
+			Transport: transportdetails{
+				MaxRetries:   d.Get("max_retries").(int),
+				RetryMaxWait: time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+
+				MaxRequestsPerSecond:  d.Get("max_requests_per_second").(float64),
+				MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+
+				HTTPProxy: d.Get("http_proxy").(string),
+				CABundle:  d.Get("ca_bundle").(string),
+				Insecure:  d.Get("insecure").(bool),
+				ReadOnly:  d.Get("read_only").(bool),
+
+				UserAgent: userAgent,
+			},
 		},
 	)
+	if client != nil {
+		client.DefaultZone = d.Get("default_zone").(string)
+		client.DefaultTimeouts = map[string]time.Duration{
+			schema.TimeoutCreate: durationFromString(d.Get("default_create_timeout").(string)),
+			schema.TimeoutUpdate: durationFromString(d.Get("default_update_timeout").(string)),
+			schema.TimeoutDelete: durationFromString(d.Get("default_delete_timeout").(string)),
+		}
+	}
+	return client, diags
 }
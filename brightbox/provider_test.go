@@ -13,12 +13,12 @@ import (
 
 func testAccProviders() map[string]func() (*schema.Provider, error) {
 	return map[string]func() (*schema.Provider, error){
-		"brightbox": func() (*schema.Provider, error) { return Provider(), nil },
+		"brightbox": func() (*schema.Provider, error) { return Provider("test"), nil },
 	}
 }
 
 func TestProvider(t *testing.T) {
-	if err := Provider().InternalValidate(); err != nil {
+	if err := Provider("test").InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
@@ -27,20 +27,12 @@ func TestProvider_badConfigs(t *testing.T) {
 	if os.Getenv("TF_ACC") != "" {
 		t.Skip("Skipping test that clears ENV as TF_ACC is set")
 	}
-	p := Provider()
+	p := Provider("test")
 	var configTests = []struct {
 		name string
 		raw  map[string]interface{}
 		err  string
 	}{
-		{
-			name: "Username without account",
-			raw: map[string]interface{}{
-				"username": "fred",
-				"password": "fred",
-			},
-			err: "Must specify Account with User Credentials",
-		},
 		{
 			name: "Apiclient with User Credentials",
 			raw: map[string]interface{}{
@@ -129,7 +121,7 @@ func TestProvider_badConfigs(t *testing.T) {
 }
 
 func TestProvider_impl(t *testing.T) {
-	var _ *schema.Provider = Provider()
+	var _ *schema.Provider = Provider("test")
 }
 
 // testAccProvider is the "main" provider instance
@@ -149,7 +141,7 @@ var testAccProvider *schema.Provider
 var testAccProviderConfigure sync.Once
 
 func init() {
-	testAccProvider = Provider()
+	testAccProvider = Provider("test")
 }
 
 func testAccPreCheck(t *testing.T) {
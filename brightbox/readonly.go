@@ -0,0 +1,106 @@
+package brightbox
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// readOnlyError reports a request refused because the provider is in
+// read only mode
+type readOnlyError struct {
+	Method string
+	Path   string
+}
+
+func (e *readOnlyError) Error() string {
+	return fmt.Sprintf("read only mode: refused to %s (%s %s)", e.Operation(), e.Method, e.Path)
+}
+
+// Operation describes the refused request in terms of what it would
+// have done, e.g. "create servers" or "lock_resource srv-12345"
+func (e *readOnlyError) Operation() string {
+	segments := strings.Split(strings.Trim(e.Path, "/"), "/")
+	if len(segments) > 1 && isVersionSegment(segments[0]) {
+		segments = segments[1:]
+	}
+	target := strings.Join(segments, "/")
+	// API actions such as lock_resource, map and resize are sent to
+	// a resource. Orbit paths start with the account instead.
+	if len(segments) >= 3 && !strings.HasPrefix(segments[0], "acc-") {
+		switch e.Method {
+		case http.MethodPost, http.MethodPut:
+			return segments[len(segments)-1] + " " + segments[len(segments)-2]
+		}
+	}
+	switch e.Method {
+	case http.MethodPost:
+		if len(segments) == 1 {
+			return "create " + segments[0]
+		}
+		return "update " + target
+	case http.MethodPut, http.MethodPatch:
+		return "update " + target
+	case http.MethodDelete:
+		return "delete " + target
+	}
+	return strings.ToLower(e.Method) + " " + target
+}
+
+// versionSegmentRegexp matches the API version at the start of a
+// path, such as 1.0 for the API or v1 for Orbit
+var versionSegmentRegexp = regexp.MustCompile(`^v?\d+(\.\d+)?$`)
+
+func isVersionSegment(segment string) bool {
+	return versionSegmentRegexp.MatchString(segment)
+}
+
+func readOnlyDiagnostic(err *readOnlyError) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Read only mode prevented the provider from trying to %s", err.Operation()),
+		Detail: fmt.Sprintf(
+			"The provider is configured with read_only = true, so the %s request to %s was refused. "+
+				"Remove read_only to make changes.",
+			err.Method, err.Path,
+		),
+	}
+}
+
+// readOnlyTransport refuses any request that could change state,
+// allowing only the token requests needed to authenticate
+type readOnlyTransport struct {
+	base     http.RoundTripper
+	tokenURL string
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+	if t.isTokenRequest(req) {
+		return t.base.RoundTrip(req)
+	}
+	tflog.SubsystemWarn(req.Context(), requestLogSubsystem(req), "Read only mode: refusing request", map[string]interface{}{
+		"http_method": req.Method,
+		"http_url":    req.URL.Redacted(),
+	})
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, &readOnlyError{Method: req.Method, Path: req.URL.Path}
+}
+
+func (t *readOnlyTransport) isTokenRequest(req *http.Request) bool {
+	if req.Method != http.MethodPost || t.tokenURL == "" {
+		return false
+	}
+	u := *req.URL
+	u.RawQuery = ""
+	return u.String() == t.tokenURL
+}
@@ -0,0 +1,85 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"gotest.tools/v3/assert"
+)
+
+func TestReadOnlyOperation(t *testing.T) {
+	testCases := []struct {
+		method string
+		path   string
+		result string
+	}{
+		{http.MethodPost, "/1.0/servers", "create servers"},
+		{http.MethodPut, "/1.0/servers/srv-12345", "update servers/srv-12345"},
+		{http.MethodDelete, "/1.0/volumes/vol-12345", "delete volumes/vol-12345"},
+		{http.MethodPost, "/1.0/servers/srv-12345/lock_resource", "lock_resource srv-12345"},
+		{http.MethodPost, "/1.0/cloud_ips/cip-12345/map", "map cip-12345"},
+		{http.MethodPost, "/1.0/volumes/vol-12345/resize", "resize vol-12345"},
+		{http.MethodPut, "/v1/acc-12345/container", "update acc-12345/container"},
+		{http.MethodPut, "/v1/acc-12345/container/object", "update acc-12345/container/object"},
+		{http.MethodPut, "/1.0/servers/srv-12345/unlock_resource", "unlock_resource srv-12345"},
+		{http.MethodPost, "/volumes/vol-12345/resize", "resize vol-12345"},
+		{http.MethodDelete, "/vpn/vpn-12345", "delete vpn/vpn-12345"},
+		{http.MethodPut, "/v2/acc-12345/container", "update acc-12345/container"},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.method+" "+tcase.path, func(t *testing.T) {
+			err := &readOnlyError{Method: tcase.method, Path: tcase.path}
+			assert.Equal(t, err.Operation(), tcase.result)
+		})
+	}
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	var mutations int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.URL.Path != "/token/" {
+			mutations++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token/":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tokenURL, err := (&endpoint.Config{BaseURL: server.URL}).TokenURL()
+	assert.NilError(t, err)
+	httpClient, diags := newHTTPClient(context.Background(), transportdetails{ReadOnly: true, TokenURL: tokenURL})
+	assert.Assert(t, !diags.HasError())
+	ctx := contextWithHTTPClient(context.Background(), httpClient)
+
+	conf, err := confFromAuthd(context.Background(), authdetails{APIClient: "cli-12345", APISecret: "secret", APIURL: server.URL})
+	assert.NilError(t, err)
+	client, err := brightbox.Connect(ctx, conf)
+	assert.NilError(t, err)
+
+	_, err = client.Servers(ctx)
+	assert.NilError(t, err)
+
+	_, err = client.CreateServer(ctx, brightbox.ServerOptions{})
+	assert.ErrorContains(t, err, "read only mode")
+	diag := brightboxFromErr(err)
+	assert.Equal(t, diag.Summary, "Read only mode prevented the provider from trying to create servers")
+
+	_, err = client.LockServer(ctx, "srv-12345")
+	assert.Equal(t, brightboxFromErr(err).Summary, "Read only mode prevented the provider from trying to lock_resource srv-12345")
+
+	assert.Equal(t, mutations, 0)
+}
@@ -0,0 +1,119 @@
+package brightbox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// regionEndpoints holds the API and Orbit endpoints for a Brightbox
+// region
+type regionEndpoints struct {
+	APIURL   string
+	OrbitURL string
+}
+
+const defaultRegion = "gb1"
+
+var regions = map[string]regionEndpoints{
+	"gb1": {
+		APIURL:   endpoint.DefaultBaseURL,
+		OrbitURL: endpoint.DefaultOrbitBaseURL,
+	},
+	"gb1s": {
+		APIURL:   "https://api.gb1s.brightbox.com/",
+		OrbitURL: "https://orbit.gb1s.brightbox.com/",
+	},
+}
+
+func regionNames() []string {
+	result := make([]string, 0, len(regions))
+	for name := range regions {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// regionFromURL identifies the region a known API or Orbit endpoint
+// belongs to. Custom endpoints return an empty string.
+func regionFromURL(rawURL string, selector func(regionEndpoints) string) string {
+	host := hostOf(rawURL)
+	if host == "" {
+		return ""
+	}
+	for name, r := range regions {
+		if hostOf(selector(r)) == host {
+			return name
+		}
+	}
+	return ""
+}
+
+func apiURLOf(r regionEndpoints) string   { return r.APIURL }
+func orbitURLOf(r regionEndpoints) string { return r.OrbitURL }
+
+// applyRegion fills in any missing endpoints from the region table
+// and rejects endpoints that point at different regions. If no region
+// is given, it is inferred from whichever endpoint has been supplied.
+func applyRegion(ctx context.Context, authd authdetails) (authdetails, diag.Diagnostics) {
+	region := authd.Region
+	if region == "" {
+		region = regionFromURL(authd.APIURL, apiURLOf)
+	}
+	if region == "" {
+		region = regionFromURL(authd.OrbitURL, orbitURLOf)
+	}
+	if region == "" {
+		if authd.APIURL != "" || authd.OrbitURL != "" {
+			tflog.Debug(ctx, "Custom endpoints configured, skipping region checks")
+			return authd, nil
+		}
+		region = defaultRegion
+	}
+	endpoints, ok := regions[region]
+	if !ok {
+		return authd, diag.Errorf("Unknown region %q. Expected one of %v", region, regionNames())
+	}
+	tflog.Debug(ctx, "Using region", map[string]interface{}{"region": region})
+	authd.Region = region
+	if authd.APIURL == "" {
+		authd.APIURL = endpoints.APIURL
+	}
+	if authd.OrbitURL == "" {
+		authd.OrbitURL = endpoints.OrbitURL
+	}
+
+	var diags diag.Diagnostics
+	if apiRegion := regionFromURL(authd.APIURL, apiURLOf); apiRegion != "" && apiRegion != region {
+		diags = append(diags, regionMismatch("API", authd.APIURL, apiRegion, region))
+	}
+	if orbitRegion := regionFromURL(authd.OrbitURL, orbitURLOf); orbitRegion != "" && orbitRegion != region {
+		diags = append(diags, regionMismatch("Orbit", authd.OrbitURL, orbitRegion, region))
+	}
+	return authd, diags
+}
+
+func regionMismatch(service string, rawURL string, found string, expected string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%s endpoint is in the wrong region", service),
+		Detail: fmt.Sprintf(
+			"The %s endpoint %s belongs to region %s, but the provider is configured for region %s",
+			service, rawURL, found, expected,
+		),
+	}
+}
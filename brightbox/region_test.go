@@ -0,0 +1,77 @@
+package brightbox
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyRegion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       authdetails
+		apiURL   string
+		orbitURL string
+		err      string
+	}{
+		{
+			name:     "defaults to gb1",
+			in:       authdetails{},
+			apiURL:   "https://api.gb1.brightbox.com/",
+			orbitURL: "https://orbit.brightbox.com/",
+		},
+		{
+			name:     "explicit region",
+			in:       authdetails{Region: "gb1s"},
+			apiURL:   "https://api.gb1s.brightbox.com/",
+			orbitURL: "https://orbit.gb1s.brightbox.com/",
+		},
+		{
+			name:     "region inferred from api url",
+			in:       authdetails{APIURL: "https://api.gb1s.brightbox.com"},
+			apiURL:   "https://api.gb1s.brightbox.com",
+			orbitURL: "https://orbit.gb1s.brightbox.com/",
+		},
+		{
+			name:     "custom endpoints are left alone",
+			in:       authdetails{APIURL: "https://api.example.com", OrbitURL: "https://orbit.brightbox.com"},
+			apiURL:   "https://api.example.com",
+			orbitURL: "https://orbit.brightbox.com",
+		},
+		{
+			name:     "override within region",
+			in:       authdetails{Region: "gb1", APIURL: "https://api.gb1.brightbox.com/"},
+			apiURL:   "https://api.gb1.brightbox.com/",
+			orbitURL: "https://orbit.brightbox.com/",
+		},
+		{
+			name: "api url in another region",
+			in:   authdetails{Region: "gb1", APIURL: "https://api.gb1s.brightbox.com/"},
+			err:  "API endpoint is in the wrong region",
+		},
+		{
+			name: "orbit url in another region",
+			in:   authdetails{APIURL: "https://api.gb1s.brightbox.com/", OrbitURL: "https://orbit.brightbox.com/"},
+			err:  "Orbit endpoint is in the wrong region",
+		},
+		{
+			name: "unknown region",
+			in:   authdetails{Region: "xx1"},
+			err:  `Unknown region "xx1". Expected one of [gb1 gb1s]`,
+		},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			authd, diags := applyRegion(context.Background(), tcase.in)
+			if tcase.err != "" {
+				assert.Assert(t, diags.HasError())
+				assert.Equal(t, diags[0].Summary, tcase.err)
+				return
+			}
+			assert.Assert(t, !diags.HasError())
+			assert.Equal(t, authd.APIURL, tcase.apiURL)
+			assert.Equal(t, authd.OrbitURL, tcase.orbitURL)
+		})
+	}
+}
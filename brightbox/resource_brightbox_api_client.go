@@ -15,9 +15,8 @@ func resourceBrightboxAPIClient() *schema.Resource {
 		ReadContext:   resourceBrightboxAPIClientRead,
 		UpdateContext: resourceBrightboxAPIClientUpdate,
 		DeleteContext: resourceBrightboxAPIClientDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -117,6 +116,7 @@ func setAPIClientAttributes(
 	var err error
 
 	d.SetId(apiClient.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", apiClient.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
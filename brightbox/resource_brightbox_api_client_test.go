@@ -171,7 +171,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			apiClients, err := client.APIClient.APIClients(ctx)
+			bbClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			apiClients, err := bbClient.APIClients(ctx)
 			if err != nil {
 				return err
 			}
@@ -181,7 +185,7 @@ func init() {
 				}
 				if isTestName(apiClient.Name) {
 					log.Printf("[INFO] removing %s named %s", apiClient.ID, apiClient.Name)
-					if _, err := client.APIClient.DestroyAPIClient(ctx, apiClient.ID); err != nil {
+					if _, err := bbClient.DestroyAPIClient(ctx, apiClient.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", apiClient.ID, err)
 					}
 				}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/brightbox/gobrightbox/v2/enums/cloudipstatus"
 	"github.com/brightbox/gobrightbox/v2/enums/mode"
 	"github.com/brightbox/gobrightbox/v2/enums/transportprotocol"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,12 +25,12 @@ func resourceBrightboxCloudIP() *schema.Resource {
 		ReadContext:   resourceBrightboxCloudIPRead,
 		UpdateContext: resourceBrightboxCloudIPUpdateAndRemap,
 		DeleteContext: resourceBrightboxCloudIPUnassignAndDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
 			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
@@ -198,6 +198,7 @@ func setCloudIPAttributes(
 	var err error
 
 	d.SetId(cloudipInstance.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", cloudipInstance.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -258,7 +259,6 @@ func setCloudIPAttributes(
 			diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 		}
 	}
-	log.Printf("[DEBUG] PortTranslator details are %#v", cloudipInstance.PortTranslators)
 	portTranslators := make([]map[string]interface{}, len(cloudipInstance.PortTranslators))
 	for i, portTranslator := range cloudipInstance.PortTranslators {
 		portTranslators[i] = map[string]interface{}{
@@ -279,6 +279,7 @@ func resourceBrightboxCloudIPCreateAndAssign(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
+	ctx = NewLogContext(ctx, "Cloud IP", "", "create")
 	targetID, ok := d.GetOk("target")
 	diags := resourceBrightboxCloudIPCreate(ctx, d, meta)
 	if !ok || diags.HasError() {
@@ -289,12 +290,12 @@ func resourceBrightboxCloudIPCreateAndAssign(
 		d,
 		meta,
 		targetID.(string),
-		d.Timeout(schema.TimeoutCreate),
+		resourceTimeout(d, meta, schema.TimeoutCreate),
 	)
 	if err != nil {
 		return brightboxFromErrSlice(err)
 	}
-	log.Printf("[DEBUG] setting details from returned object")
+	logReturnedObject(ctx, cloudIPInstance)
 	return setCloudIPAttributes(d, cloudIPInstance)
 }
 
@@ -305,8 +306,14 @@ func assignCloudIP(
 	target string,
 	timeout time.Duration,
 ) (*brightbox.CloudIP, error) {
-	log.Printf("[INFO] Attaching %s to %s", d.Id(), target)
-	client := meta.(*CompositeClient).APIClient
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Attaching Cloud IP", map[string]interface{}{
+		logFieldObjectID: d.Id(),
+		"target":         target,
+	})
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return nil, err
+	}
 	return assuredMapCloudIP(
 		ctx,
 		client,
@@ -322,10 +329,16 @@ func unassignCloudIP(
 	meta interface{},
 	timeout time.Duration,
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	targetID, _ := d.GetChange("target")
 	if targetID.(string) != "" {
-		log.Printf("[INFO] Detaching %s from %s", d.Id(), targetID.(string))
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Detaching Cloud IP", map[string]interface{}{
+			logFieldObjectID: d.Id(),
+			"target":         targetID.(string),
+		})
 		_, err := assuredUnmapCloudIP(
 			ctx,
 			client,
@@ -333,10 +346,10 @@ func unassignCloudIP(
 			timeout,
 		)
 		if err == nil {
-			log.Printf("[DEBUG] detached cleanly")
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detached cleanly")
 			return nil
 		}
-		log.Printf("[DEBUG] detachment failed - checking for out of band detachment")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detachment failed - checking for out of band detachment")
 		instance, readerr := client.CloudIP(ctx, d.Id())
 		if readerr != nil {
 			return diag.FromErr(readerr)
@@ -344,7 +357,7 @@ func unassignCloudIP(
 		if !detachedCloudIP(instance) {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] detached out of band")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detached out of band")
 	}
 	return nil
 }
@@ -364,12 +377,13 @@ func resourceBrightboxCloudIPUpdateAndRemap(
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	ctx = NewLogContext(ctx, "Cloud IP", d.Id(), "update")
 	if d.HasChange("target") {
-		log.Printf("[INFO] Cloud IP target has changed, updating...")
-		diags = append(diags, unassignCloudIP(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))...)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Cloud IP target has changed, updating")
+		diags = append(diags, unassignCloudIP(ctx, d, meta, resourceTimeout(d, meta, schema.TimeoutUpdate))...)
 		if targetID, ok := d.GetOk("target"); ok {
 			if target := targetID.(string); target != "" {
-				_, err := assignCloudIP(ctx, d, meta, target, d.Timeout(schema.TimeoutUpdate))
+				_, err := assignCloudIP(ctx, d, meta, target, resourceTimeout(d, meta, schema.TimeoutUpdate))
 				if err != nil {
 					diags = append(diags, brightboxFromErr(err))
 				}
@@ -462,7 +476,9 @@ func cloudipStateRefresh(ctx context.Context, client *brightbox.Client, cloudipI
 	return func() (interface{}, string, error) {
 		cloudipInstance, err := client.CloudIP(ctx, cloudipID)
 		if err != nil {
-			log.Printf("Error on Cloud IP State Refresh: %s", err)
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Error on Cloud IP State Refresh", map[string]interface{}{
+				"error": err.Error(),
+			})
 			return nil, "", err
 		}
 		return cloudipInstance, cloudipInstance.Status.String(), nil
@@ -474,7 +490,7 @@ func resourceBrightboxCloudIPUnassignAndDelete(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	diags := unassignCloudIP(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))
+	diags := unassignCloudIP(ctx, d, meta, resourceTimeout(d, meta, schema.TimeoutDelete))
 	if diags.HasError() {
 		return diags
 	}
@@ -425,7 +425,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.CloudIPs(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.CloudIPs(ctx)
 			if err != nil {
 				return err
 			}
@@ -435,7 +439,7 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.DestroyCloudIP(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyCloudIP(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
@@ -3,9 +3,11 @@ package brightbox
 import (
 	"context"
 	"errors"
-	"log"
+	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -18,20 +20,26 @@ func resourceBrightboxCreate[O, I any](
 	setter func(*schema.ResourceData, *O) diag.Diagnostics,
 ) schema.CreateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, "", "create")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
-		log.Printf("[INFO] Creating %s", objectName)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating "+objectName)
 		var objectOptions I
 		errs := updater(d, &objectOptions)
 		if errs.HasError() {
 			return errs
 		}
-		log.Printf("[INFO] %s create configuration: %+v", objectName, objectOptions)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" create configuration", map[string]interface{}{
+			logFieldOptions: redacted(objectOptions),
+		})
 		object, err := creator(client, ctx, objectOptions)
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] setting details from returned object: %+v", *object)
+		logReturnedObject(ctx, object)
 		return setter(d, object)
 	}
 }
@@ -59,16 +67,20 @@ func resourceBrightboxReadStatus[O any](
 ) schema.ReadContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 		target := d.Id()
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, target, "read")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
-		log.Printf("[DEBUG] %s resource read called for %s", objectName, target)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" resource read called")
 
 		object, err := reader(client, ctx, target)
 		if err != nil {
 			var apierror *brightbox.APIError
 			if !d.IsNewResource() && errors.As(err, &apierror) {
 				if apierror.StatusCode == 404 {
-					log.Printf("[WARN] %s not found, removing from state: %s", objectName, target)
+					tflog.SubsystemWarn(ctx, logSubsystemAPI, objectName+" not found, removing from state")
 					d.SetId("")
 					return nil
 				}
@@ -76,12 +88,12 @@ func resourceBrightboxReadStatus[O any](
 			return brightboxFromErrSlice(err)
 		}
 		if missing(object) {
-			log.Printf("[WARN] %s not found, removing from state: %s", objectName, target)
+			tflog.SubsystemWarn(ctx, logSubsystemAPI, objectName+" not found, removing from state")
 			d.SetId("")
 			return nil
 		}
 
-		log.Printf("[DEBUG] setting details from returned object: %+v", *object)
+		logReturnedObject(ctx, object)
 		return setter(d, object)
 	}
 }
@@ -93,9 +105,13 @@ func datasourceBrightboxRead[O any](
 	finderGenerator func(*schema.ResourceData) (func(O) bool, diag.Diagnostics),
 ) schema.ReadContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, "", "read")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
-		log.Printf("[DEBUG] %s data read called. Retrieving object list", objectName)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" data read called. Retrieving object list")
 
 		objects, err := reader(client, ctx)
 		if err != nil {
@@ -119,8 +135,8 @@ func datasourceBrightboxRead[O any](
 		}
 
 		result := &results[0]
-		log.Printf("[DEBUG] Single %s found", objectName)
-		log.Printf("[DEBUG] %+v", *result)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Single "+objectName+" found")
+		logReturnedObject(ctx, result)
 		return setter(d, result)
 
 	}
@@ -133,9 +149,13 @@ func datasourceBrightboxRecentRead[O brightbox.CreateDated](
 	finderGenerator func(*schema.ResourceData) (func(O) bool, diag.Diagnostics),
 ) schema.ReadContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, "", "read")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
-		log.Printf("[DEBUG] %s data read called. Retrieving object list", objectName)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" data read called. Retrieving object list")
 
 		objects, err := reader(client, ctx)
 		if err != nil {
@@ -157,7 +177,7 @@ func datasourceBrightboxRecentRead[O brightbox.CreateDated](
 				return diag.Errorf("Your query returned more than one result (found %d entries). Please try a more "+
 					"specific search criteria.", len(results))
 			}
-			log.Printf("[DEBUG] Multiple results found and `most_recent` is set")
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Multiple results found and `most_recent` is set")
 			result = mostRecent(results)
 		} else if len(results) < 1 {
 			return diag.Errorf("Your query returned no results. " +
@@ -165,8 +185,8 @@ func datasourceBrightboxRecentRead[O brightbox.CreateDated](
 		} else {
 			result = &results[0]
 		}
-		log.Printf("[DEBUG] Single %s found", objectName)
-		log.Printf("[DEBUG] %+v", *result)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Single "+objectName+" found")
+		logReturnedObject(ctx, result)
 		return setter(d, result)
 
 	}
@@ -193,20 +213,26 @@ func resourceBrightboxUpdateWithLock[O, I any](
 	locksetter schema.UpdateContextFunc,
 ) schema.UpdateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, d.Id(), "update")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
 		objectOpts := newFromID(d.Id())
 		errs := updater(d, objectOpts)
 		if errs.HasError() {
 			return errs
 		}
-		log.Printf("[DEBUG] %s update configuration: %+v", objectName, objectOpts)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" update configuration", map[string]interface{}{
+			logFieldOptions: redacted(objectOpts),
+		})
 
 		object, err := putter(client, ctx, *objectOpts)
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] setting details from returned object: %+v", *object)
+		logReturnedObject(ctx, object)
 		if locksetter != nil && d.HasChange("locked") {
 			return locksetter(ctx, d, meta)
 		}
@@ -219,14 +245,18 @@ func resourceBrightboxDelete[O any](
 	objectName string,
 ) schema.DeleteContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*CompositeClient).APIClient
+		ctx = NewLogContext(ctx, objectName, d.Id(), "delete")
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 
-		log.Printf("[INFO] Deleting %s %s", objectName, d.Id())
-		_, err := deleter(client, ctx, d.Id())
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Deleting "+objectName)
+		_, err = deleter(client, ctx, d.Id())
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] Deleted cleanly")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Deleted cleanly")
 		return nil
 	}
 }
@@ -244,16 +274,19 @@ func resourceBrightboxDeleteAndWait[O any](
 			return diags
 		}
 
-		client := meta.(*CompositeClient).APIClient
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 		stateConf := retry.StateChangeConf{
 			Pending:    pending,
 			Target:     target,
 			Refresh:    refresher(client, ctx, d.Id()),
-			Timeout:    d.Timeout(schema.TimeoutDelete),
+			Timeout:    resourceTimeout(d, meta, schema.TimeoutDelete),
 			Delay:      checkDelay,
 			MinTimeout: minimumRefreshWait,
 		}
-		_, err := stateConf.WaitForStateContext(ctx)
+		_, err = stateConf.WaitForStateContext(ctx)
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
@@ -269,11 +302,16 @@ func resourceBrightboxSetLockState[O any](
 ) schema.UpdateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{},
 	) diag.Diagnostics {
+		ctx = NewLogContext(ctx, "", d.Id(), "lock")
 		locked := d.Get("locked").(bool)
-		log.Printf("[INFO] Setting lock state to %v", locked)
-		client := meta.(*CompositeClient).APIClient
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Setting lock state", map[string]interface{}{
+			"locked": locked,
+		})
+		client, err := meta.(*CompositeClient).APIClient()
+		if err != nil {
+			return brightboxFromErrSlice(err)
+		}
 		var object *O
-		var err error
 		if locked {
 			object, err = locker(client, ctx, d.Id())
 		} else {
@@ -282,7 +320,118 @@ func resourceBrightboxSetLockState[O any](
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] setting details from returned object: %+v", *object)
+		logReturnedObject(ctx, object)
 		return setter(d, object)
 	}
 }
+
+// logReturnedObject traces the object the API returned before it is
+// copied into state
+func logReturnedObject(ctx context.Context, object interface{}) {
+	tflog.SubsystemTrace(ctx, logSubsystemAPI, "Setting details from returned object", map[string]interface{}{
+		logFieldObject: redacted(object),
+	})
+}
+
+// resourceTimeout returns the timeout for the operation given by key.
+// Unless the resource sets it in its timeouts block, the provider
+// default for the operation is used. When the configuration isn't
+// available, as on delete, a timeout equal to the schema default is
+// assumed not to have been set.
+func resourceTimeout(d *schema.ResourceData, meta interface{}, key string) time.Duration {
+	timeout := d.Timeout(key)
+	composite, ok := meta.(*CompositeClient)
+	if !ok || composite == nil {
+		return timeout
+	}
+	providerDefault, ok := composite.DefaultTimeouts[key]
+	if !ok {
+		return timeout
+	}
+	if configured, known := timeoutConfigured(d, key); known {
+		if configured {
+			return timeout
+		}
+		return providerDefault
+	}
+	if timeout == defaultTimeout {
+		return providerDefault
+	}
+	return timeout
+}
+
+// applyResourceTimeouts runs each operation the resource declares a
+// timeout for under the deadline from resourceTimeout, rather than the
+// SDK's, so provider defaults bound the whole operation and not just
+// the waits within it
+func applyResourceTimeouts(resource *schema.Resource) {
+	if resource.Timeouts == nil {
+		return
+	}
+	if resource.Timeouts.Create != nil && resource.CreateContext != nil {
+		resource.CreateWithoutTimeout = withResourceTimeout(resource.CreateContext, schema.TimeoutCreate)
+		resource.CreateContext = nil
+	}
+	if resource.Timeouts.Update != nil && resource.UpdateContext != nil {
+		resource.UpdateWithoutTimeout = withResourceTimeout(resource.UpdateContext, schema.TimeoutUpdate)
+		resource.UpdateContext = nil
+	}
+	if resource.Timeouts.Delete != nil && resource.DeleteContext != nil {
+		resource.DeleteWithoutTimeout = withResourceTimeout(resource.DeleteContext, schema.TimeoutDelete)
+		resource.DeleteContext = nil
+	}
+}
+
+func withResourceTimeout[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](
+	operation F,
+	key string,
+) F {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, cancel := context.WithTimeout(ctx, resourceTimeout(d, meta, key))
+		defer cancel()
+		return operation(ctx, d, meta)
+	}
+}
+
+// timeoutConfigured reports whether the timeout for the operation is
+// set in the resource configuration, and whether the configuration
+// could be examined
+func timeoutConfigured(d *schema.ResourceData, key string) (configured bool, known bool) {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() || !raw.Type().IsObjectType() || !raw.Type().HasAttribute(schema.TimeoutsConfigKey) {
+		return false, false
+	}
+	timeouts := raw.GetAttr(schema.TimeoutsConfigKey)
+	if timeouts.IsNull() || !timeouts.IsKnown() {
+		return false, true
+	}
+	if !timeouts.Type().IsObjectType() || !timeouts.Type().HasAttribute(key) {
+		return false, true
+	}
+	return !timeouts.GetAttr(key).IsNull(), true
+}
+
+// setDefaultZone places new resources that don't specify a zone into
+// the provider default zone, so the plan shows the zone that will be
+// used.
+func setDefaultZone(
+	ctx context.Context,
+	d *schema.ResourceDiff,
+	meta interface{},
+) error {
+	if d.Id() != "" {
+		return nil
+	}
+	composite, ok := meta.(*CompositeClient)
+	if !ok || composite == nil || composite.DefaultZone == "" {
+		return nil
+	}
+	zone, diags := d.GetRawConfigAt(cty.GetAttrPath("zone"))
+	if diags.HasError() || !zone.IsNull() {
+		return nil
+	}
+	tflog.Debug(ctx, "Using default zone", map[string]interface{}{
+		"zone": composite.DefaultZone,
+	})
+	return d.SetNew("zone", composite.DefaultZone)
+}
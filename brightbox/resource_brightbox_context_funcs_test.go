@@ -4,19 +4,121 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"testing"
+	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
 )
 
+func TestResourceTimeout(t *testing.T) {
+	meta := &CompositeClient{
+		DefaultTimeouts: map[string]time.Duration{
+			schema.TimeoutCreate: 30 * time.Minute,
+			schema.TimeoutUpdate: 20 * time.Minute,
+		},
+	}
+	d := resourceBrightboxVolume().Data(&terraform.InstanceState{ID: "vol-12345"})
+	assert.Equal(t, resourceTimeout(d, meta, schema.TimeoutCreate), 30*time.Minute)
+	assert.Equal(t, resourceTimeout(d, meta, schema.TimeoutUpdate), 20*time.Minute)
+	assert.Equal(t, resourceTimeout(d, meta, schema.TimeoutDelete), defaultTimeout)
+	assert.Equal(t, resourceTimeout(d, unconfiguredClient(), schema.TimeoutCreate), defaultTimeout)
+	assert.Equal(t, meta.DefaultTimeout(schema.TimeoutUpdate), 20*time.Minute)
+	assert.Equal(t, meta.DefaultTimeout(schema.TimeoutDelete), defaultTimeout)
+	assert.Equal(t, (*CompositeClient)(nil).DefaultTimeout(schema.TimeoutCreate), defaultTimeout)
+
+	overridden := &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Minute),
+		},
+	}
+	d = overridden.Data(&terraform.InstanceState{ID: "vol-12345"})
+	assert.Equal(t, resourceTimeout(d, meta, schema.TimeoutCreate), 45*time.Minute)
+}
+
+func TestResourceTimeoutBoundsOperation(t *testing.T) {
+	meta := &CompositeClient{
+		DefaultTimeouts: map[string]time.Duration{
+			schema.TimeoutCreate: 30 * time.Minute,
+		},
+	}
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected time.Duration
+	}{
+		"provider default": {
+			config:   map[string]interface{}{"size": 10240},
+			expected: 30 * time.Minute,
+		},
+		"timeouts block": {
+			config: map[string]interface{}{
+				"size":     10240,
+				"timeouts": map[string]interface{}{"create": "10m"},
+			},
+			expected: 10 * time.Minute,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			volume := resourceBrightboxVolume()
+			var remaining time.Duration
+			volume.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				deadline, ok := ctx.Deadline()
+				assert.Assert(t, ok)
+				remaining = time.Until(deadline)
+				d.SetId("vol-12345")
+				return nil
+			}
+			applyResourceTimeouts(volume)
+
+			ctx := context.Background()
+			diff, err := volume.Diff(ctx, nil, terraform.NewResourceConfigRaw(tc.config), meta)
+			assert.NilError(t, err)
+			_, diags := volume.Apply(ctx, nil, diff, meta)
+			assert.Assert(t, !diags.HasError(), diags)
+			assert.Assert(t, remaining > tc.expected-time.Minute && remaining <= tc.expected, remaining)
+		})
+	}
+}
+
+func TestResourceTimeoutCancelsOperation(t *testing.T) {
+	meta := newFakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		http.NotFound(w, r)
+	})
+	meta.DefaultTimeouts = map[string]time.Duration{
+		schema.TimeoutCreate: 100 * time.Millisecond,
+	}
+	group := Provider("test").ResourcesMap["brightbox_server_group"]
+
+	ctx := context.Background()
+	diff, err := group.Diff(ctx, nil, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "web"}), meta)
+	assert.NilError(t, err)
+	start := time.Now()
+	_, diags := group.Apply(ctx, nil, diff, meta)
+	assert.Assert(t, diags.HasError())
+	assert.Assert(t, strings.Contains(diags[0].Summary+diags[0].Detail, "context deadline exceeded"), diags)
+	assert.Assert(t, time.Since(start) < time.Second)
+}
+
 func testAccCheckBrightboxDestroyBuilder[I any](
 	objectName string,
 	instance func(*brightbox.Client, context.Context, string) (*I, error),
 ) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*CompositeClient).APIClient
+		client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+		if err != nil {
+			return err
+		}
 
 		for _, rs := range s.RootModule().Resources {
 			if rs.Type != objectName {
@@ -76,7 +178,10 @@ func testAccCheckBrightboxObjectExists[O any](
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("No %s ID is set", objectName)
 		}
-		client := testAccProvider.Meta().(*CompositeClient).APIClient
+		client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+		if err != nil {
+			return err
+		}
 		retrieveobject, err := instance(client, context.Background(), rs.Primary.ID)
 		if err != nil {
 			return err
@@ -2,11 +2,11 @@ package brightbox
 
 import (
 	"context"
-	"log"
 	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
 	"github.com/brightbox/gobrightbox/v2/enums/databaseserverstatus"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,9 +20,9 @@ func resourceBrightboxDatabaseServer() *schema.Resource {
 		ReadContext:   resourceBrightboxDatabaseServerRead,
 		UpdateContext: resourceBrightboxDatabaseServerResizeAndUpdate,
 		DeleteContext: resourceBrightboxDatabaseServerDeleteAndWait,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		CustomizeDiff: setDefaultZone,
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -235,6 +235,7 @@ func setDatabaseServerAttributes(
 	var err error
 
 	d.SetId(databaseServer.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", databaseServer.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -311,7 +312,9 @@ func databaseServerStateRefresh(client *brightbox.Client, ctx context.Context, d
 	return func() (interface{}, string, error) {
 		databaseServer, err := client.DatabaseServer(ctx, databaseServerID)
 		if err != nil {
-			log.Printf("Error on Database Server State Refresh: %s", err)
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Error on Database Server State Refresh", map[string]interface{}{
+				"error": err.Error(),
+			})
 			return nil, "", err
 		}
 		return databaseServer, databaseServer.Status.String(), nil
@@ -323,12 +326,18 @@ func resourceBrightboxDatabaseServerResizeAndUpdate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Database Server", d.Id(), "update")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[DEBUG] Database Resize and Update called for %s", d.Id())
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Database Resize and Update called")
 	if d.HasChange("database_type") {
 		newDatabaseType := d.Get("database_type").(string)
-		log.Printf("[INFO] Changing database type to %v", newDatabaseType)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Changing database type", map[string]interface{}{
+			"database_type": newDatabaseType,
+		})
 		_, err := client.ResizeDatabaseServer(
 			ctx,
 			d.Id(),
@@ -346,9 +355,13 @@ func resourceBrightboxDatabaseServerCreateAndWait(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Database Server", "", "create")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[INFO]] Creating Database Server")
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating Database Server")
 	var databaseServerOpts brightbox.DatabaseServerOptions
 
 	errs := addUpdateableDatabaseServerOptions(d, &databaseServerOpts)
@@ -367,8 +380,9 @@ func resourceBrightboxDatabaseServerCreateAndWait(
 	zone := &databaseServerOpts.Zone
 	assignString(d, &zone, "zone")
 
-	log.Printf("[DEBUG] Database Server create configuration %+v", databaseServerOpts)
-	outputDatabaseServerOptions(&databaseServerOpts)
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Database Server create configuration", map[string]interface{}{
+		logFieldOptions: redacted(databaseServerOpts),
+	})
 
 	databaseServer, err := client.CreateDatabaseServer(ctx, databaseServerOpts)
 	if err != nil {
@@ -376,14 +390,15 @@ func resourceBrightboxDatabaseServerCreateAndWait(
 	}
 
 	d.SetId(databaseServer.ID)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldObjectID, d.Id())
 
 	if databaseServer.AdminPassword == "" {
-		log.Printf("[WARN] No password returned for Cloud SQL server %s", databaseServer.ID)
+		tflog.SubsystemWarn(ctx, logSubsystemAPI, "No password returned for Cloud SQL server")
 	} else {
 		d.Set("admin_password", databaseServer.AdminPassword)
 	}
 
-	log.Printf("[INFO] Waiting for Database Server (%s) to become available", d.Id())
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Waiting for Database Server to become available")
 
 	stateConf := retry.StateChangeConf{
 		Pending: []string{
@@ -393,7 +408,7 @@ func resourceBrightboxDatabaseServerCreateAndWait(
 			databaseserverstatus.Active.String(),
 		},
 		Refresh:    databaseServerStateRefresh(client, ctx, databaseServer.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Timeout:    resourceTimeout(d, meta, schema.TimeoutCreate),
 		Delay:      checkDelay,
 		MinTimeout: minimumRefreshWait,
 	}
@@ -404,42 +419,3 @@ func resourceBrightboxDatabaseServerCreateAndWait(
 
 	return resourceBrightboxSetDatabaseServerLockState(ctx, d, meta)
 }
-
-func outputDatabaseServerOptions(opts *brightbox.DatabaseServerOptions) {
-	if opts.Name != nil {
-		log.Printf("[DEBUG] Database Server Name %v", *opts.Name)
-	}
-	if opts.Description != nil {
-		log.Printf("[DEBUG] Database Server Description %v", *opts.Description)
-	}
-	if opts.Engine != "" {
-		log.Printf("[DEBUG] Database Server Engine %v", opts.Engine)
-	}
-	if opts.Version != "" {
-		log.Printf("[DEBUG] Database Server Version %v", opts.Version)
-	}
-	if opts.DatabaseType != "" {
-		log.Printf("[DEBUG] Database Server Type %v", opts.DatabaseType)
-	}
-	if opts.AllowAccess != nil {
-		log.Printf("[DEBUG] Database Server AllowAccess %#v", opts.AllowAccess)
-	}
-	if opts.Snapshot != "" {
-		log.Printf("[DEBUG] Database Server Snapshot %q", opts.Snapshot)
-	}
-	if opts.SnapshotsRetention != nil {
-		log.Printf("[DEBUG] Database Server Snapshots Retention %q", *opts.SnapshotsRetention)
-	}
-	if opts.SnapshotsSchedule != nil {
-		log.Printf("[DEBUG] Database Server Snapshots Schedule %q", *opts.SnapshotsSchedule)
-	}
-	if opts.Zone != "" {
-		log.Printf("[DEBUG] Database Server Zone %v", opts.Zone)
-	}
-	if opts.MaintenanceWeekday != nil {
-		log.Printf("[DEBUG] Database Server MaintenanceWeekday %v", *opts.MaintenanceWeekday)
-	}
-	if opts.MaintenanceHour != nil {
-		log.Printf("[DEBUG] Database Server MaintenanceHour %v", *opts.MaintenanceHour)
-	}
-}
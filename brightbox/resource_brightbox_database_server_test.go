@@ -510,7 +510,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.DatabaseServers(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.DatabaseServers(ctx)
 			if err != nil {
 				return err
 			}
@@ -520,10 +524,10 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.UnlockDatabaseServer(ctx, object.ID); err != nil {
+					if _, err := apiClient.UnlockDatabaseServer(ctx, object.ID); err != nil {
 						log.Printf("error unlocking %s during sweep: %s", object.ID, err)
 					}
-					if _, err := client.APIClient.DestroyDatabaseServer(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyDatabaseServer(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
@@ -2,9 +2,9 @@ package brightbox
 
 import (
 	"context"
-	"log"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -17,9 +17,8 @@ func resourceBrightboxFirewallPolicy() *schema.Resource {
 		ReadContext:   resourceBrightboxFirewallPolicyRead,
 		UpdateContext: resourceBrightboxFirewallPolicyUpdateAndRemap,
 		DeleteContext: resourceBrightboxFirewallPolicyDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Update: schema.DefaultTimeout(defaultTimeout),
@@ -100,6 +99,7 @@ func setFirewallPolicyAttributes(
 	var err error
 
 	d.SetId(firewallPolicy.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", firewallPolicy.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -127,6 +127,7 @@ func resourceBrightboxFirewallPolicyCreateAndAssign(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
+	ctx = NewLogContext(ctx, "Firewall Policy", "", "create")
 	targetID, ok := d.GetOk("server_group")
 	diags := resourceBrightboxFirewallPolicyCreate(ctx, d, meta)
 	if !ok || diags.HasError() {
@@ -136,7 +137,7 @@ func resourceBrightboxFirewallPolicyCreateAndAssign(
 	if err != nil {
 		return brightboxFromErrSlice(err)
 	}
-	log.Printf("[DEBUG] setting details from returned object")
+	logReturnedObject(ctx, FirewallPolicyInstance)
 	return setFirewallPolicyAttributes(d, FirewallPolicyInstance)
 }
 
@@ -146,8 +147,14 @@ func assignFirewallPolicy(
 	meta interface{},
 	target string,
 ) (*brightbox.FirewallPolicy, error) {
-	log.Printf("[INFO] Attaching %s to %s", d.Id(), target)
-	client := meta.(*CompositeClient).APIClient
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Attaching Firewall Policy", map[string]interface{}{
+		logFieldObjectID: d.Id(),
+		"server_group":   target,
+	})
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return nil, err
+	}
 	return client.ApplyFirewallPolicy(
 		ctx,
 		d.Id(),
@@ -160,20 +167,26 @@ func unassignFirewallPolicy(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	targetID, _ := d.GetChange("server_group")
 	if target := targetID.(string); target != "" {
-		log.Printf("[INFO] Detaching %s from %s", d.Id(), target)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Detaching Firewall Policy", map[string]interface{}{
+			logFieldObjectID: d.Id(),
+			"server_group":   target,
+		})
 		_, err := client.RemoveFirewallPolicy(
 			ctx,
 			d.Id(),
 			brightbox.FirewallPolicyAttachment{ServerGroup: target},
 		)
 		if err == nil {
-			log.Printf("[DEBUG] detached cleanly")
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detached cleanly")
 			return nil
 		}
-		log.Printf("[DEBUG] detachment failed - checking for out of band detachment")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detachment failed - checking for out of band detachment")
 		instance, readerr := client.FirewallPolicy(ctx, d.Id())
 		if readerr != nil {
 			return diag.FromErr(readerr)
@@ -181,7 +194,7 @@ func unassignFirewallPolicy(
 		if !detachedFirewallPolicy(instance) {
 			return brightboxFromErrSlice(err)
 		}
-		log.Printf("[DEBUG] detached out of band")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detached out of band")
 	}
 	return nil
 }
@@ -197,8 +210,9 @@ func resourceBrightboxFirewallPolicyUpdateAndRemap(
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	ctx = NewLogContext(ctx, "Firewall Policy", d.Id(), "update")
 	if d.HasChange("server_group") {
-		log.Printf("[INFO] Server Group changed, updating...")
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Server Group changed, updating")
 		diags = append(diags, unassignFirewallPolicy(ctx, d, meta)...)
 		if targetID, ok := d.GetOk("server_group"); ok {
 			if target := targetID.(string); target != "" {
@@ -339,7 +339,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.FirewallPolicies(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.FirewallPolicies(ctx)
 			if err != nil {
 				return err
 			}
@@ -349,7 +353,7 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.DestroyFirewallPolicy(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyFirewallPolicy(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
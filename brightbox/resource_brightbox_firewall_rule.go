@@ -2,10 +2,10 @@ package brightbox
 
 import (
 	"context"
-	"log"
 	"regexp"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -24,9 +24,8 @@ func resourceBrightboxFirewallRule() *schema.Resource {
 		ReadContext:   resourceBrightboxFirewallRuleRead,
 		UpdateContext: resourceBrightboxFirewallRuleUpdateAndRegenerate,
 		DeleteContext: resourceBrightboxFirewallRuleDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -193,6 +192,7 @@ func setFirewallRuleAttributes(
 	var err error
 
 	d.SetId(firewallRule.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("firewall_policy", firewallRule.FirewallPolicy.ID)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -233,17 +233,17 @@ func resourceBrightboxFirewallRuleUpdateAndRegenerate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-
+	ctx = NewLogContext(ctx, "Firewall Rule", d.Id(), "update")
 	if d.HasChange("firewall_policy") {
-		log.Printf("[INFO] Firewall Policy changed, regenerating rule")
-		log.Printf("[INFO] Removing original rule %s", d.Id())
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Firewall Policy changed, regenerating rule")
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Removing original rule")
 
 		errs := resourceBrightboxFirewallRuleDelete(ctx, d, meta)
 		if errs.HasError() {
 			return errs
 		}
 
-		log.Printf("[INFO] Creating new rule")
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating new rule")
 		return resourceBrightboxFirewallRuleCreate(ctx, d, meta)
 	}
 	return resourceBrightboxFirewallRuleUpdate(ctx, d, meta)
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
@@ -13,6 +12,7 @@ import (
 	"github.com/brightbox/gobrightbox/v2/enums/listenerprotocol"
 	"github.com/brightbox/gobrightbox/v2/enums/loadbalancerstatus"
 	"github.com/brightbox/gobrightbox/v2/enums/proxyprotocol"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -34,9 +34,8 @@ func resourceBrightboxLoadBalancer() *schema.Resource {
 		ReadContext:   resourceBrightboxLoadBalancerRead,
 		UpdateContext: resourceBrightboxLoadBalancerUpdate,
 		DeleteContext: resourceBrightboxLoadBalancerDeleteAndWait,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -62,10 +61,29 @@ func resourceBrightboxLoadBalancer() *schema.Resource {
 			},
 
 			"certificate_private_key": {
-				Description: "RSA private key used to sign the certificate in PEM format",
-				Type:        schema.TypeString,
-				Optional:    true,
-				StateFunc:   hashString,
+				Description:   "RSA private key used to sign the certificate in PEM format",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"certificate_private_key_wo"},
+				StateFunc:     hashString,
+			},
+
+			"certificate_private_key_wo": {
+				Description:   "RSA private key used to sign the certificate in PEM format. Never stored in state",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"certificate_private_key"},
+				RequiredWith:  []string{"certificate_private_key_wo_version"},
+			},
+
+			"certificate_private_key_wo_version": {
+				Description:  "Change to send the value of certificate_private_key_wo to the load balancer",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"certificate_private_key_wo"},
+				ValidateFunc: validation.IntAtLeast(1),
 			},
 
 			"domains": {
@@ -77,7 +95,7 @@ func resourceBrightboxLoadBalancer() *schema.Resource {
 					Type:         schema.TypeString,
 					ValidateFunc: validation.StringMatch(dnsNameRegexp, "must be a valid DNS name"),
 				},
-				ConflictsWith: []string{"certificate_pem", "certificate_private_key"},
+				ConflictsWith: []string{"certificate_pem", "certificate_private_key", "certificate_private_key_wo"},
 			},
 
 			"healthcheck": {
@@ -317,6 +335,7 @@ func addUpdateableLoadBalancerOptions(
 	assignEnum(d, &opts.Policy, "policy")
 	assignString(d, &opts.CertificatePem, "certificate_pem")
 	assignString(d, &opts.CertificatePrivateKey, "certificate_private_key")
+	assignWriteOnlyString(d, &opts.CertificatePrivateKey, "certificate_private_key_wo")
 	assignString(d, &opts.SslMinimumVersion, "ssl_minimum_version")
 	assignBool(d, &opts.HTTPSRedirect, "https_redirect")
 	if d.HasChange("domains") {
@@ -336,6 +355,7 @@ func setLoadBalancerAttributes(
 	var err error
 
 	d.SetId(loadBalancer.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", loadBalancer.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -380,12 +400,10 @@ func setLoadBalancerAttributes(
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 	}
-	log.Printf("[DEBUG] Healthcheck details are %+v", loadBalancer.Healthcheck)
 	err = d.Set("healthcheck", mapFromHealthcheck(&loadBalancer.Healthcheck))
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 	}
-	log.Printf("[DEBUG] Certificate details are %+v", loadBalancer.Certificate)
 	return diags
 }
 
@@ -404,7 +422,9 @@ func loadBalancerStateRefresh(client *brightbox.Client, ctx context.Context, loa
 	return func() (interface{}, string, error) {
 		loadBalancer, err := client.LoadBalancer(ctx, loadBalancerID)
 		if err != nil {
-			log.Printf("Error on Load Balancer State Refresh: %s", err)
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Error on Load Balancer State Refresh", map[string]interface{}{
+				"error": err.Error(),
+			})
 			return nil, "", err
 		}
 		return loadBalancer, loadBalancer.Status.String(), nil
@@ -478,9 +498,13 @@ func resourceBrightboxLoadBalancerCreateAndWait(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Load Balancer", "", "create")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[INFO]] Creating Load Balancer")
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating Load Balancer")
 	var loadBalancerOpts brightbox.LoadBalancerOptions
 
 	errs := addUpdateableLoadBalancerOptions(d, &loadBalancerOpts)
@@ -488,8 +512,9 @@ func resourceBrightboxLoadBalancerCreateAndWait(
 		return errs
 	}
 
-	log.Printf("[DEBUG] Load Balancer create configuration %+v", loadBalancerOpts)
-	outputLoadBalancerOptions(&loadBalancerOpts)
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Load Balancer create configuration", map[string]interface{}{
+		logFieldOptions: redacted(loadBalancerOpts),
+	})
 
 	loadBalancer, err := client.CreateLoadBalancer(ctx, loadBalancerOpts)
 	if err != nil {
@@ -497,8 +522,9 @@ func resourceBrightboxLoadBalancerCreateAndWait(
 	}
 
 	d.SetId(loadBalancer.ID)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldObjectID, d.Id())
 
-	log.Printf("[INFO] Waiting for Load Balancer (%s) to become available", d.Id())
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Waiting for Load Balancer to become available")
 
 	stateConf := retry.StateChangeConf{
 		Pending: []string{
@@ -508,7 +534,7 @@ func resourceBrightboxLoadBalancerCreateAndWait(
 			loadbalancerstatus.Active.String(),
 		},
 		Refresh:    loadBalancerStateRefresh(client, ctx, loadBalancer.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Timeout:    resourceTimeout(d, meta, schema.TimeoutCreate),
 		Delay:      checkDelay,
 		MinTimeout: minimumRefreshWait,
 	}
@@ -593,30 +619,3 @@ func expandNodes(configured []interface{}) []brightbox.LoadBalancerNode {
 	}
 	return nodes
 }
-
-func outputLoadBalancerOptions(opts *brightbox.LoadBalancerOptions) {
-	if opts.Name != nil {
-		log.Printf("[DEBUG] Load Balancer Name %v", *opts.Name)
-	}
-	if opts.Nodes != nil {
-		log.Printf("[DEBUG] Load Balancer Nodes %#v", opts.Nodes)
-	}
-	if opts.Policy != 0 {
-		log.Printf("[DEBUG] Load Balancer Policy %v", opts.Policy.String())
-	}
-	if opts.Listeners != nil {
-		log.Printf("[DEBUG] Load Balancer Listeners %#v", opts.Listeners)
-	}
-	if opts.Healthcheck != nil {
-		log.Printf("[DEBUG] Load Balancer Healthcheck %#v", *opts.Healthcheck)
-	}
-	if opts.Domains != nil {
-		log.Printf("[DEBUG] Load Balancer Domains %#v", opts.Domains)
-	}
-	if opts.CertificatePem != nil {
-		log.Printf("[DEBUG] Load Balancer CertificatePem %v", *opts.CertificatePem)
-	}
-	if opts.CertificatePrivateKey != nil {
-		log.Printf("[DEBUG] Load Balancer CertificatePrivateKey %v", *opts.CertificatePrivateKey)
-	}
-}
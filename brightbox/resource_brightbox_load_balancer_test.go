@@ -620,7 +620,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.LoadBalancers(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.LoadBalancers(ctx)
 			if err != nil {
 				return err
 			}
@@ -630,10 +634,10 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.UnlockLoadBalancer(ctx, object.ID); err != nil {
+					if _, err := apiClient.UnlockLoadBalancer(ctx, object.ID); err != nil {
 						log.Printf("error unlocking %s during sweep: %s", object.ID, err)
 					}
-					if _, err := client.APIClient.DestroyLoadBalancer(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyLoadBalancer(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
@@ -2,12 +2,12 @@ package brightbox
 
 import (
 	"context"
-	"log"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -24,9 +24,8 @@ func resourceBrightboxContainer() *schema.Resource {
 		ReadContext:   resourceBrightboxContainerRead,
 		UpdateContext: resourceBrightboxContainerUpdate,
 		DeleteContext: resourceBrightboxContainerDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      nameImporter(),
+		Identity:      nameIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -131,19 +130,27 @@ func resourceBrightboxContainerCreate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).OrbitClient
+	ctx = NewLogContext(ctx, "Container", d.Id(), "create")
+	client, err := meta.(*CompositeClient).OrbitClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	client.ProviderClient.Context = ctx
 
-	log.Printf("[INFO] Creating Container")
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Creating Container")
 	createOpts := getCreateContainerOptions(d)
-	log.Printf("[DEBUG] Container create configuration: %#v", createOpts)
 	currentContainerPath := containerPath(d)
-	log.Printf("[DEBUG] Create path is: %s", currentContainerPath)
+	tflog.SubsystemDebug(ctx, logSubsystemOrbit, "Container create configuration", map[string]interface{}{
+		logFieldOptions: redacted(createOpts),
+		"path":          currentContainerPath,
+	})
 	container, err := containers.Create(client, currentContainerPath, createOpts).Extract()
 	if err != nil {
 		return brightboxFromErrSlice(err)
 	}
-	log.Printf("[INFO] Container created with TransID %s", container.TransID)
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Container created", map[string]interface{}{
+		"trans_id": container.TransID,
+	})
 	d.SetId(currentContainerPath)
 	return resourceBrightboxContainerRead(ctx, d, meta)
 }
@@ -153,15 +160,21 @@ func resourceBrightboxContainerDelete(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).OrbitClient
+	ctx = NewLogContext(ctx, "Container", d.Id(), "delete")
+	client, err := meta.(*CompositeClient).OrbitClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	client.ProviderClient.Context = ctx
 
-	log.Printf("[INFO] Deleting Container")
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Deleting Container")
 	container, err := containers.Delete(client, d.Id()).Extract()
 	if err != nil {
 		return brightboxFromErrSlice(err)
 	}
-	log.Printf("[INFO] Container deleted with TransID %s", container.TransID)
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Container deleted", map[string]interface{}{
+		"trans_id": container.TransID,
+	})
 	return nil
 }
 
@@ -170,17 +183,25 @@ func resourceBrightboxContainerUpdate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).OrbitClient
+	ctx = NewLogContext(ctx, "Container", d.Id(), "update")
+	client, err := meta.(*CompositeClient).OrbitClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	client.ProviderClient.Context = ctx
 
-	log.Printf("[INFO] Updating Container")
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Updating Container")
 	updateOpts := getUpdateContainerOptions(d)
-	log.Printf("[INFO] Container update configuration: %#v", updateOpts)
+	tflog.SubsystemDebug(ctx, logSubsystemOrbit, "Container update configuration", map[string]interface{}{
+		logFieldOptions: redacted(updateOpts),
+	})
 	container, err := containers.Update(client, d.Id(), updateOpts).Extract()
 	if err != nil {
 		return brightboxFromErrSlice(err)
 	}
-	log.Printf("[INFO] Container updated with TransID %s", container.TransID)
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Container updated", map[string]interface{}{
+		"trans_id": container.TransID,
+	})
 	return resourceBrightboxContainerRead(ctx, d, meta)
 }
 
@@ -189,17 +210,23 @@ func resourceBrightboxContainerRead(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).OrbitClient
+	ctx = NewLogContext(ctx, "Container", d.Id(), "read")
+	client, err := meta.(*CompositeClient).OrbitClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	client.ProviderClient.Context = ctx
 
-	log.Printf("[DEBUG] Reading container: %s", d.Id())
+	tflog.SubsystemDebug(ctx, logSubsystemOrbit, "Reading container")
 	result := containers.Get(client, d.Id(), nil)
 	getresult, err := result.Extract()
 	if err != nil {
-		log.Printf("[DEBUG] Checking if container is deleted")
+		tflog.SubsystemDebug(ctx, logSubsystemOrbit, "Checking if container is deleted")
 		return diag.FromErr(CheckDeleted(d, result.Err, "container"))
 	}
-	log.Printf("[INFO] Container read with TransID %s", getresult.TransID)
+	tflog.SubsystemInfo(ctx, logSubsystemOrbit, "Container read", map[string]interface{}{
+		"trans_id": getresult.TransID,
+	})
 	metadata, _ := result.ExtractMetadata()
 	return setContainerAttributes(d, getresult, metadata)
 }
@@ -242,8 +269,7 @@ func setContainerAttributes(
 	attr *containers.GetHeader,
 	metadata map[string]string,
 ) diag.Diagnostics {
-	var diags diag.Diagnostics
-	log.Printf("[DEBUG] Setting Container details from %#v", attr)
+	diags := setNameIdentity(d)
 	if err := d.Set("name", d.Id()); err != nil {
 		diags = append(diags, brightboxFromErr(err))
 	}
@@ -98,7 +98,10 @@ func TestAccBrightboxOrbitContainer_metadata(t *testing.T) {
 }
 
 func testAccCheckBrightboxOrbitContainerDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*CompositeClient).OrbitClient
+	client, err := testAccProvider.Meta().(*CompositeClient).OrbitClient()
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	client.ProviderClient.Context = ctx
@@ -194,7 +197,11 @@ func init() {
 			if errs.HasError() {
 				return fmt.Errorf("error obtaining cloud client")
 			}
-			result, err := containers.Delete(client.OrbitClient, containerName).Extract()
+			orbitClient, err := client.OrbitClient()
+			if err != nil {
+				return err
+			}
+			result, err := containers.Delete(orbitClient, containerName).Extract()
 			if err != nil {
 				if _, ok := err.(gophercloud.ErrDefault404); !ok {
 					return err
@@ -3,12 +3,13 @@ package brightbox
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
 	"github.com/brightbox/gobrightbox/v2/enums/serverstatus"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,6 +17,8 @@ import (
 
 const (
 	userdataSizeLimit = 16384
+	powerStateRunning = "running"
+	powerStateStopped = "stopped"
 )
 
 func resourceBrightboxServer() *schema.Resource {
@@ -25,12 +28,18 @@ func resourceBrightboxServer() *schema.Resource {
 		ReadContext:   resourceBrightboxServerRead,
 		UpdateContext: resourceBrightboxServerUpdate,
 		DeleteContext: resourceBrightboxServerDeleteAndWait,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		CustomizeDiff: customdiff.All(
+			setDefaultZone,
+			customdiff.ComputedIf("status", func(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
+				return d.HasChange("power_state")
+			}),
+		),
+		Importer: idImporter(),
+		Identity: idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
 			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
@@ -169,6 +178,20 @@ func resourceBrightboxServer() *schema.Resource {
 				Computed:    true,
 			},
 
+			"power_state": {
+				Description:  "Whether the server should be running or stopped",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{powerStateRunning, powerStateStopped}, false),
+			},
+
+			"force_stop": {
+				Description: "Stop the server by cutting the power, rather than asking the operating system to shut down",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+
 			"status": {
 				Description: "Current state of server",
 				Type:        schema.TypeString,
@@ -190,7 +213,7 @@ func resourceBrightboxServer() *schema.Resource {
 				Description:   "Data made available to Cloud Init",
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"user_data_base64"},
+				ConflictsWith: []string{"user_data_base64", "user_data_wo"},
 				StateFunc:     hashString,
 				ValidateFunc:  validation.StringIsNotWhiteSpace,
 			},
@@ -199,10 +222,29 @@ func resourceBrightboxServer() *schema.Resource {
 				Description:   "Base64 encoded data made available to Cloud Init",
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"user_data"},
+				ConflictsWith: []string{"user_data", "user_data_wo"},
 				ValidateFunc:  validation.StringIsBase64,
 			},
 
+			"user_data_wo": {
+				Description:   "Data made available to Cloud Init. Never stored in state",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"user_data", "user_data_base64"},
+				RequiredWith:  []string{"user_data_wo_version"},
+				ValidateFunc:  validation.StringIsNotWhiteSpace,
+			},
+
+			"user_data_wo_version": {
+				Description:  "Change to send the value of user_data_wo to the server",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"user_data_wo"},
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
 			"username": {
 				Description: "Username to use when logging into a server",
 				Type:        schema.TypeString,
@@ -267,23 +309,26 @@ func addUpdateableServerOptions(
 	assignString(d, &opts.SnapshotsSchedule, "snapshots_schedule")
 	assignString(d, &opts.SnapshotsRetention, "snapshots_retention")
 	assignStringSet(d, &opts.ServerGroups, "server_groups")
-	if !d.HasChanges("user_data", "user_data_base64") {
+	if !d.HasChanges("user_data", "user_data_base64", "user_data_wo_version") {
 		return nil
 	}
 	// Definitely have user data changes that need sending
 	encodedUserData := ""
 	if d.HasChange("user_data") {
 		if userData, ok := d.GetOk("user_data"); ok {
-			log.Printf("[DEBUG] UserData to encode: %s", userData.(string))
 			encodedUserData = base64Encode(userData.(string))
 		}
 	}
 	if d.HasChange("user_data_base64") {
 		if userData, ok := d.GetOk("user_data_base64"); ok {
-			log.Printf("[DEBUG] Encoded Userdata found, passing through")
 			encodedUserData = userData.(string)
 		}
 	}
+	if d.HasChange("user_data_wo_version") {
+		if userData := writeOnlyString(d, "user_data_wo"); userData != "" {
+			encodedUserData = base64Encode(userData)
+		}
+	}
 	if len(encodedUserData) > userdataSizeLimit {
 		return diag.Errorf(
 			"The supplied user_data contains %d bytes after encoding, this exeeds the limit of %d bytes",
@@ -303,6 +348,7 @@ func setServerAttributes(
 	var err error
 
 	d.SetId(server.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("image", server.Image.ID)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -319,6 +365,12 @@ func setServerAttributes(
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 	}
+	if powerState := serverPowerState(server.Status); powerState != "" {
+		err = d.Set("power_state", powerState)
+		if err != nil {
+			diags = append(diags, diag.Errorf("unexpected: %s", err)...)
+		}
+	}
 	err = d.Set("locked", server.Locked)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -373,7 +425,7 @@ func setServerAttributes(
 		if err != nil {
 			diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 		}
-		err = d.Set("ipv6_hostname", "ipv6."+server.Fqdn)
+		err = d.Set("ipv6_hostname", IPv6Hostname(server.Fqdn))
 		if err != nil {
 			diags = append(diags, diag.Errorf("unexpected: %s", err)...)
 		}
@@ -441,11 +493,68 @@ func serverUnavailable(obj *brightbox.Server) bool {
 		obj.Status == serverstatus.Failed
 }
 
+// serverPowerState describes a server status as a power_state, or
+// returns "" for a server that is neither running nor stopped
+func serverPowerState(status serverstatus.Enum) string {
+	switch status {
+	case serverstatus.Active:
+		return powerStateRunning
+	case serverstatus.Inactive:
+		return powerStateStopped
+	}
+	return ""
+}
+
+// setServerPowerState starts or stops the server to match the
+// configured power_state, and waits for the matching status. A new
+// server is always stopped by cutting the power, as its operating
+// system may still be booting and ignore a request to shut down.
+func setServerPowerState(
+	ctx context.Context,
+	d *schema.ResourceData,
+	client *brightbox.Client,
+	server *brightbox.Server,
+	timeout time.Duration,
+) diag.Diagnostics {
+	powerState, ok := d.GetOk("power_state")
+	if !ok || powerState.(string) == serverPowerState(server.Status) {
+		return nil
+	}
+	var command func(*brightbox.Client, context.Context, string) (*brightbox.Server, error)
+	var pending, target serverstatus.Enum
+	switch powerState.(string) {
+	case powerStateRunning:
+		command = (*brightbox.Client).StartServer
+		pending, target = serverstatus.Inactive, serverstatus.Active
+	case powerStateStopped:
+		command = (*brightbox.Client).ShutdownServer
+		if d.IsNewResource() || d.Get("force_stop").(bool) {
+			command = (*brightbox.Client).StopServer
+		}
+		pending, target = serverstatus.Active, serverstatus.Inactive
+	default:
+		return diag.Errorf("unexpected power_state %q", powerState)
+	}
+
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Changing Server power state", map[string]interface{}{
+		"power_state": powerState,
+	})
+	if _, err := command(client, ctx, server.ID); err != nil {
+		return brightboxFromErrSlice(err)
+	}
+	if _, err := WaitForServerStatus(ctx, client, server.ID, []serverstatus.Enum{pending}, target, timeout); err != nil {
+		return brightboxFromErrSlice(err)
+	}
+	return nil
+}
+
 func serverStateRefresh(client *brightbox.Client, ctx context.Context, serverID string) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		serverInstance, err := client.Server(ctx, serverID)
 		if err != nil {
-			log.Printf("Error on Server State Refresh: %s", err)
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Error on Server State Refresh", map[string]interface{}{
+				"error": err.Error(),
+			})
 			return nil, "", err
 		}
 		return serverInstance, serverInstance.Status.String(), nil
@@ -457,9 +566,13 @@ func resourceBrightboxServerCreateAndWait(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Server", "", "create")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[INFO] Creating Server")
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating Server")
 	var serverOpts brightbox.ServerOptions
 
 	errs := addUpdateableServerOptions(d, &serverOpts)
@@ -472,7 +585,9 @@ func resourceBrightboxServerCreateAndWait(
 	assignBool(d, &serverOpts.DiskEncrypted, "disk_encrypted")
 	addBlockStorageOptions(d, &serverOpts)
 
-	log.Printf("[DEBUG] Server create configuration: %+v", serverOpts)
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Server create configuration", map[string]interface{}{
+		logFieldOptions: redacted(serverOpts),
+	})
 
 	server, err := client.CreateServer(ctx, serverOpts)
 	if err != nil {
@@ -480,8 +595,9 @@ func resourceBrightboxServerCreateAndWait(
 	}
 
 	d.SetId(server.ID)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldObjectID, d.Id())
 
-	log.Printf("[INFO] Waiting for Server (%s) to become available", d.Id())
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Waiting for Server to become available")
 
 	stateConf := retry.StateChangeConf{
 		Pending: []string{
@@ -492,7 +608,7 @@ func resourceBrightboxServerCreateAndWait(
 			serverstatus.Inactive.String(),
 		},
 		Refresh:    serverStateRefresh(client, ctx, server.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Timeout:    resourceTimeout(d, meta, schema.TimeoutCreate),
 		Delay:      checkDelay,
 		MinTimeout: minimumRefreshWait,
 	}
@@ -503,7 +619,11 @@ func resourceBrightboxServerCreateAndWait(
 
 	server = result.(*brightbox.Server)
 
-	return resourceBrightboxSetServerLockState(ctx, d, meta)
+	diags := setServerPowerState(ctx, d, client, server, resourceTimeout(d, meta, schema.TimeoutCreate))
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceBrightboxSetServerLockState(ctx, d, meta)...)
 }
 
 func resourceBrightboxServerUpdate(
@@ -511,22 +631,27 @@ func resourceBrightboxServerUpdate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Server", d.Id(), "update")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[DEBUG] Server update called for %s", d.Id())
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Server update called")
 	serverOpts := brightbox.ServerOptions{
 		ID: d.Id(),
 	}
 	var server *brightbox.Server
-	var err error
 	var diags diag.Diagnostics
 
-	if d.HasChanges("name", "server_groups", "user_data", "user_data_base64", "snapshots_retention", "snapshots_schedule") {
+	if d.HasChanges("name", "server_groups", "user_data", "user_data_base64", "user_data_wo_version", "snapshots_retention", "snapshots_schedule") {
 		diags = append(diags, addUpdateableServerOptions(d, &serverOpts)...)
 		if diags.HasError() {
 			return diags
 		}
-		log.Printf("[DEBUG] Server update configuration: %+v", serverOpts)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Server update configuration", map[string]interface{}{
+			logFieldOptions: redacted(serverOpts),
+		})
 
 		server, err = client.UpdateServer(ctx, serverOpts)
 		if err != nil {
@@ -547,7 +672,9 @@ func resourceBrightboxServerUpdate(
 	}
 	if d.HasChange("type") {
 		newServerType := d.Get("type").(string)
-		log.Printf("[INFO] Changing server type to %v", newServerType)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Changing server type", map[string]interface{}{
+			"server_type": newServerType,
+		})
 		server, err = client.ResizeServer(
 			ctx,
 			d.Id(),
@@ -560,11 +687,17 @@ func resourceBrightboxServerUpdate(
 	if diags.HasError() {
 		return diags
 	}
+	if d.HasChange("power_state") {
+		diags = append(diags, setServerPowerState(ctx, d, client, server, resourceTimeout(d, meta, schema.TimeoutUpdate))...)
+		if diags.HasError() {
+			return diags
+		}
+	}
 
 	if d.HasChange("locked") {
-		return resourceBrightboxSetServerLockState(ctx, d, meta)
+		return append(diags, resourceBrightboxSetServerLockState(ctx, d, meta)...)
 	}
-	return resourceBrightboxServerRead(ctx, d, meta)
+	return append(diags, resourceBrightboxServerRead(ctx, d, meta)...)
 }
 
 func addBlockStorageOptions(
@@ -593,9 +726,15 @@ func addBlockStorageOptions(
 }
 
 func setUserDataDetails(d *schema.ResourceData, base64Userdata string) diag.Diagnostics {
+	if _, wo := d.GetOk("user_data_wo_version"); wo {
+		// Write-only user data is not recorded, not even as a hash
+		if err := d.Set("user_data", ""); err != nil {
+			return brightboxFromErrSlice(err)
+		}
+		return nil
+	}
 	_, b64 := d.GetOk("user_data_base64")
 	if b64 {
-		log.Printf("[DEBUG] encoded user_data requested, setting user_data_base64")
 		if err := d.Set("user_data_base64", base64Userdata); err != nil {
 			return brightboxFromErrSlice(err)
 		}
@@ -610,7 +749,6 @@ func setUserDataDetails(d *schema.ResourceData, base64Userdata string) diag.Diag
 			}
 			hash = userDataHashSum(userData)
 		}
-		log.Printf("[DEBUG] decrypted user_data requested, setting user_data to %q", hash)
 		if err := d.Set("user_data", hash); err != nil {
 			return brightboxFromErrSlice(err)
 		}
@@ -3,10 +3,10 @@ package brightbox
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,9 +19,8 @@ func resourceBrightboxServerGroup() *schema.Resource {
 		ReadContext:   resourceBrightboxServerGroupRead,
 		UpdateContext: resourceBrightboxServerGroupUpdate,
 		DeleteContext: resourceBrightboxServerGroupClearAndDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -110,6 +109,7 @@ func setServerGroupAttributes(
 	var err error
 
 	d.SetId(serverGroup.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", serverGroup.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -142,14 +142,18 @@ func resourceBrightboxServerGroupClearAndDelete(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Server Group", d.Id(), "delete")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
 	serverGroup, err := client.ServerGroup(ctx, d.Id())
 	if err != nil {
 		return diag.Errorf("Error retrieving Server Group details: %s", err)
 	}
 	if len(serverGroup.Servers) > 0 {
-		err := clearServerList(ctx, client, serverGroup, d.Timeout(schema.TimeoutDelete))
+		err := clearServerList(ctx, client, serverGroup, resourceTimeout(d, meta, schema.TimeoutDelete))
 		if err != nil {
 			return brightboxFromErrSlice(err)
 		}
@@ -161,7 +165,10 @@ func clearServerList(ctx context.Context, client *brightbox.Client, iniitialServ
 	serverID := iniitialServerGroup.ID
 	serverList := iniitialServerGroup.Servers
 	serverIds := serverGroupMemberListFromNodes(serverList)
-	log.Printf("[INFO] Removing servers %v from server group %s", serverIds, serverID)
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Removing servers from server group", map[string]interface{}{
+		"server_group_id": serverID,
+		"server_ids":      serverIds,
+	})
 	_, err := client.RemoveServersFromServerGroup(ctx, serverID, serverIds)
 	if err != nil {
 		return fmt.Errorf("Error removing servers from server group %s", serverID)
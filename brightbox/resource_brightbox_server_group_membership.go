@@ -4,12 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -24,6 +24,11 @@ func resourceBrightboxServerGroupMembership() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceBrightboxServerGroupMembershipImport,
 		},
+		Identity: serverGroupMembershipIdentity(),
+		// Adding and removing servers changes the identity in place
+		ResourceBehavior: schema.ResourceBehavior{
+			MutableIdentity: true,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
@@ -62,16 +67,20 @@ func resourceBrightboxServerGroupMembershipRead(
 	target := d.Get("group").(string)
 	reader := (*brightbox.Client).ServerGroup
 	setter := setServerGroupMembershipAttributes
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, objectName, target, "read")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[DEBUG] %s resource read called for %s", objectName, target)
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, objectName+" resource read called")
 
 	object, err := reader(client, ctx, target)
 	if err != nil {
 		var apierror *brightbox.APIError
 		if !d.IsNewResource() && errors.As(err, &apierror) {
 			if apierror.StatusCode == 404 {
-				log.Printf("[WARN] %s not found, removing from state: %s", objectName, target)
+				tflog.SubsystemWarn(ctx, logSubsystemAPI, objectName+" not found, removing from state")
 				d.SetId("")
 				return nil
 			}
@@ -79,7 +88,7 @@ func resourceBrightboxServerGroupMembershipRead(
 		return brightboxFromErrSlice(err)
 	}
 
-	log.Printf("[DEBUG] setting details from returned object: %+v", *object)
+	logReturnedObject(ctx, object)
 	return setter(d, object)
 }
 
@@ -88,17 +97,29 @@ func resourceBrightboxServerGroupMembershipCreate(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	var diags diag.Diagnostics
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "ServerGroup", d.Get("group").(string), "create")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	group := d.Get("group").(string)
 	serverList := sliceFromStringSet(d, "servers")
 
 	object, err := client.AddServersToServerGroup(ctx, group, mapServerGroupMemberList(serverList))
 	if err != nil {
-		diags = append(diags, brightboxFromErr(err))
+		return brightboxFromErrSlice(err)
 	}
-	d.SetId(id.UniqueId())
-	return append(diags, setServerGroupMembershipAttributes(d, object)...)
+	d.SetId(serverGroupMembershipID(group, serverList))
+	return setServerGroupMembershipAttributes(d, object)
+}
+
+// serverGroupMembershipID returns the ID of a membership in the
+// <group>/<server1>/... form accepted by import, with the servers
+// sorted so the same membership always has the same ID
+func serverGroupMembershipID(group string, servers []string) string {
+	sorted := append([]string(nil), servers...)
+	sort.Strings(sorted)
+	return strings.Join(append([]string{group}, sorted...), "/")
 }
 
 func mapServerGroupMemberList(list []string) brightbox.ServerGroupMemberList {
@@ -116,19 +137,23 @@ func setServerGroupMembershipAttributes(
 	var diags diag.Diagnostics
 	d.Set("group", serverGroup.ID)
 	serverList := d.Get("servers").(*schema.Set)
-	var sl []string
+	sl := []string{}
 
 	for _, server := range serverGroup.Servers {
 		if serverList.Contains(server.ID) {
 			sl = append(sl, server.ID)
 		}
 	}
+	sort.Strings(sl)
 
 	if err := d.Set("servers", sl); err != nil {
 		return append(diags, diag.Errorf("setting server list from group (%s), error: %s", serverGroup.ID, err)...)
 	}
 
-	return diags
+	return setIdentity(d, map[string]interface{}{
+		"group":   serverGroup.ID,
+		"servers": sl,
+	})
 }
 
 func resourceBrightboxServerGroupMembershipUpdate(
@@ -137,9 +162,13 @@ func resourceBrightboxServerGroupMembershipUpdate(
 	meta interface{},
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "ServerGroup", d.Get("group").(string), "update")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	if d.HasChange("servers") {
-		log.Printf("[DEBUG] ServerGroupMembership change detected, updating")
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "ServerGroupMembership change detected, updating")
 		group := d.Get("group").(string)
 
 		o, n := d.GetChange("servers")
@@ -153,9 +182,11 @@ func resourceBrightboxServerGroupMembershipUpdate(
 		os := o.(*schema.Set)
 		ns := n.(*schema.Set)
 		remove := expandStringValueList(os.Difference(ns).List())
-		log.Printf("[DEBUG] removing servers: %+v", remove)
 		add := expandStringValueList(ns.Difference(os).List())
-		log.Printf("[DEBUG] adding servers: %+v", add)
+		tflog.SubsystemDebug(ctx, logSubsystemAPI, "Changing server group members", map[string]interface{}{
+			"remove": remove,
+			"add":    add,
+		})
 
 		var object *brightbox.ServerGroup
 		var err error
@@ -171,6 +202,9 @@ func resourceBrightboxServerGroupMembershipUpdate(
 				diags = append(diags, brightboxFromErr(err))
 			}
 		}
+		if !diags.HasError() {
+			d.SetId(serverGroupMembershipID(group, expandStringValueList(ns.List())))
+		}
 		return append(diags, setServerGroupMembershipAttributes(d, object)...)
 	}
 	return resourceBrightboxServerGroupMembershipRead(ctx, d, meta)
@@ -182,30 +216,70 @@ func resourceBrightboxServerGroupMembershipDelete(
 	meta interface{},
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "ServerGroup", d.Get("group").(string), "delete")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	group := d.Get("group").(string)
 	serverList := sliceFromStringSet(d, "servers")
 
-	_, err := client.RemoveServersFromServerGroup(ctx, group, mapServerGroupMemberList(serverList))
+	_, err = client.RemoveServersFromServerGroup(ctx, group, mapServerGroupMemberList(serverList))
 	if err != nil {
 		diags = append(diags, brightboxFromErr(err))
 	}
 	return diags
 }
 
+// resourceBrightboxServerGroupMembershipImport imports a membership
+// from an ID of the form <group>/<server1>/..., or from the identity
 func resourceBrightboxServerGroupMembershipImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	idParts := strings.Split(d.Id(), "/")
-	if len(idParts) < 2 {
-		return nil, fmt.Errorf("unexpected format of ID (%q), expected <group-name>/<server-name1>/...", d.Id())
+	var group string
+	var servers []string
+	if d.Id() != "" {
+		idParts := strings.Split(d.Id(), "/")
+		if len(idParts) < 2 {
+			return nil, fmt.Errorf("unexpected format of ID (%q), expected <group-name>/<server-name1>/...", d.Id())
+		}
+		group = idParts[0]
+		servers = idParts[1:]
+	} else {
+		identity, err := d.Identity()
+		if err != nil {
+			return nil, fmt.Errorf("error getting identity: %s", err)
+		}
+		group = identity.Get("group").(string)
+		servers = expandStringValueList(identity.Get("servers").([]interface{}))
+		if group == "" || len(servers) == 0 {
+			return nil, fmt.Errorf("expected identity to contain a group and at least one server")
+		}
 	}
 
-	userName := idParts[0]
-	groupList := idParts[1:]
-
-	d.Set("group", userName)
-	d.Set("servers", groupList)
-
-	d.SetId(id.UniqueId())
+	d.Set("group", group)
+	d.Set("servers", servers)
+	d.SetId(serverGroupMembershipID(group, servers))
 
 	return []*schema.ResourceData{d}, nil
 }
+
+func serverGroupMembershipIdentity() *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				"group": {
+					Description:       "The ID of the Server Group",
+					Type:              schema.TypeString,
+					RequiredForImport: true,
+				},
+				"servers": {
+					Description:       "The IDs of the Servers in the membership, sorted",
+					Type:              schema.TypeList,
+					RequiredForImport: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			}
+		},
+	}
+}
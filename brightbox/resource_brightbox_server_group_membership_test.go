@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"testing"
 
@@ -89,9 +88,7 @@ func TestAccBrightboxServerGroupMembership_basic(t *testing.T) {
 				ResourceName:      resourceName,
 				ImportState:       true,
 				ImportStateIdFunc: testAccBrightboxServerGroupMembershipImportStateIDFunc(resourceName),
-				// We do not have a way to align IDs since the Create function uses id.UniqueId()
-				// Failed state verification, resource with ID USER/GROUP not found
-				// ImportStateVerify: true,
+				ImportStateVerify: true,
 				ImportStateCheck: func(s []*terraform.InstanceState) error {
 					if len(s) != 1 {
 						return fmt.Errorf("expected 1 state: %#v", s)
@@ -389,18 +386,21 @@ func testAccBrightboxServerGroupMembershipImportStateIDFunc(resourceName string)
 			return "", fmt.Errorf("Not found: %s", resourceName)
 		}
 
-		serverCount, _ := strconv.Atoi(rs.Primary.Attributes["servers.#"])
 		stateID := rs.Primary.Attributes["group"]
-		for i := 0; i < serverCount; i++ {
-			serverID := rs.Primary.Attributes[fmt.Sprintf("server.%d", i)]
-			stateID = fmt.Sprintf("%s/%s", stateID, serverID)
+		for key, serverID := range rs.Primary.Attributes {
+			if strings.HasPrefix(key, "servers.") && key != "servers.#" {
+				stateID = fmt.Sprintf("%s/%s", stateID, serverID)
+			}
 		}
 		return stateID, nil
 	}
 }
 
 func testAccCheckBrightboxServerGroupMembershipDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*CompositeClient).APIClient
+	client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "brightbox_server_group_membership" {
 			continue
@@ -180,7 +180,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.ServerGroups(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.ServerGroups(ctx)
 			if err != nil {
 				return err
 			}
@@ -190,7 +194,7 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.DestroyServerGroup(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyServerGroup(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
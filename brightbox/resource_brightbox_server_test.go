@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"testing"
+	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
 	"github.com/brightbox/gobrightbox/v2/enums/serverstatus"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
 )
 
 func TestAccBrightboxServer_Basic(t *testing.T) {
@@ -150,6 +154,192 @@ func TestAccBrightboxServer_userDataBase64(t *testing.T) {
 	})
 }
 
+func TestSetUserDataDetailsWriteOnly(t *testing.T) {
+	userData := base64Encode("#cloud-config")
+	d := schema.TestResourceDataRaw(t, resourceBrightboxServer().Schema, map[string]interface{}{
+		"user_data_wo_version": 1,
+	})
+	assert.Assert(t, !setUserDataDetails(d, userData).HasError())
+	assert.Equal(t, d.Get("user_data"), "")
+
+	d = schema.TestResourceDataRaw(t, resourceBrightboxServer().Schema, map[string]interface{}{})
+	assert.Assert(t, !setUserDataDetails(d, userData).HasError())
+	assert.Equal(t, d.Get("user_data"), userDataHashSum("#cloud-config"))
+}
+
+func TestAccBrightboxServer_userDataWriteOnly(t *testing.T) {
+	resourceName := "brightbox_server.foobar"
+	var afterCreate, afterUpdate brightbox.Server
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(),
+		CheckDestroy:      testAccCheckBrightboxServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxServerConfig_writeOnlyUserData(rInt, "foo:-with-character's", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxObjectExists(
+						resourceName,
+						"Server",
+						&afterCreate,
+						(*brightbox.Client).Server,
+					),
+					testAccCheckBrightboxServerUserData(&afterCreate, "foo:-with-character's"),
+					resource.TestCheckResourceAttr(resourceName, "user_data", ""),
+					resource.TestCheckNoResourceAttr(resourceName, "user_data_wo"),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxServerConfig_writeOnlyUserData(rInt, "foo:-with-different-character's", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxObjectExists(
+						resourceName,
+						"Server",
+						&afterUpdate,
+						(*brightbox.Client).Server,
+					),
+					testAccCheckBrightboxServerUserData(&afterUpdate, "foo:-with-different-character's"),
+					resource.TestCheckResourceAttr(resourceName, "user_data_wo_version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBrightboxServerUserData(server *brightbox.Server, userData string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if server.UserData != base64Encode(userData) {
+			return fmt.Errorf("Bad user data: %q", server.UserData)
+		}
+		return nil
+	}
+}
+
+func TestServerPowerState(t *testing.T) {
+	testCases := map[serverstatus.Enum]string{
+		serverstatus.Active:   powerStateRunning,
+		serverstatus.Inactive: powerStateStopped,
+		serverstatus.Creating: "",
+		serverstatus.Deleting: "",
+	}
+	for status, expected := range testCases {
+		assert.Equal(t, serverPowerState(status), expected, status.String())
+	}
+}
+
+func TestSetServerPowerStateUnchanged(t *testing.T) {
+	testCases := map[string]serverstatus.Enum{
+		"":                serverstatus.Inactive,
+		powerStateRunning: serverstatus.Active,
+		powerStateStopped: serverstatus.Inactive,
+	}
+	for powerState, status := range testCases {
+		d := resourceBrightboxServer().Data(&terraform.InstanceState{
+			ID:         "srv-12345",
+			Attributes: map[string]string{"power_state": powerState},
+		})
+		// No API calls are made, so no client is needed
+		diags := setServerPowerState(context.Background(), d, nil, &brightbox.Server{ID: "srv-12345", Status: status}, time.Minute)
+		assert.Assert(t, !diags.HasError(), diags)
+	}
+}
+
+func TestSetServerPowerStateCommand(t *testing.T) {
+	testCases := []struct {
+		powerState  string
+		force       string
+		newResource bool
+		status      serverstatus.Enum
+		expected    string
+	}{
+		{powerStateRunning, "false", false, serverstatus.Inactive, "/1.0/servers/srv-12345/start"},
+		{powerStateStopped, "false", false, serverstatus.Active, "/1.0/servers/srv-12345/shutdown"},
+		{powerStateStopped, "true", false, serverstatus.Active, "/1.0/servers/srv-12345/stop"},
+		// A server being created is stopped even if force_stop is unset
+		{powerStateStopped, "false", true, serverstatus.Active, "/1.0/servers/srv-12345/stop"},
+	}
+	for _, tc := range testCases {
+		var requested []string
+		composite := newFakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_name":"invalid_state","errors":["Server is busy"]}`))
+		})
+		client, err := composite.APIClient()
+		assert.NilError(t, err)
+		d := resourceBrightboxServer().Data(&terraform.InstanceState{
+			ID: "srv-12345",
+			Attributes: map[string]string{
+				"power_state": tc.powerState,
+				"force_stop":  tc.force,
+			},
+		})
+		if tc.newResource {
+			d.MarkNewResource()
+		}
+		diags := setServerPowerState(context.Background(), d, client, &brightbox.Server{ID: "srv-12345", Status: tc.status}, time.Minute)
+		assert.Assert(t, diags.HasError(), tc.expected)
+		assert.DeepEqual(t, requested, []string{"POST " + tc.expected})
+	}
+}
+
+func TestAccBrightboxServer_powerState(t *testing.T) {
+	resourceName := "brightbox_server.foobar"
+	var server brightbox.Server
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(),
+		CheckDestroy:      testAccCheckBrightboxServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxServerConfig_powerState(rInt, powerStateStopped),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxObjectExists(
+						resourceName,
+						"Server",
+						&server,
+						(*brightbox.Client).Server,
+					),
+					resource.TestCheckResourceAttr(resourceName, "power_state", powerStateStopped),
+					resource.TestCheckResourceAttr(resourceName, "status", serverstatus.Inactive.String()),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxServerConfig_powerState(rInt, powerStateRunning),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", powerStateRunning),
+					resource.TestCheckResourceAttr(resourceName, "status", serverstatus.Active.String()),
+				),
+			},
+			// Powering off by hand is drift, which the next apply corrects
+			{
+				PreConfig: func() {
+					client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := client.StopServer(context.Background(), server.ID); err != nil {
+						t.Fatal(err)
+					}
+					if _, err := WaitForServerStatus(context.Background(), client, server.ID,
+						[]serverstatus.Enum{serverstatus.Active}, serverstatus.Inactive, defaultTimeout); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccCheckBrightboxServerConfig_powerState(rInt, powerStateRunning),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", powerStateRunning),
+					resource.TestCheckResourceAttr(resourceName, "status", serverstatus.Active.String()),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBrightboxServer_serverGroup(t *testing.T) {
 	serverResourceName := "brightbox_server.foobar"
 	resourceName := "brightbox_server_group.barfoo"
@@ -309,7 +499,10 @@ func testAccCheckBrightboxServerAndGroupDestroy(s *terraform.State) error {
 }
 
 func testAccCheckBrightboxServerDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*CompositeClient).APIClient
+	client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "brightbox_server" {
@@ -397,6 +590,33 @@ func TestAccBrightboxServer_Update(t *testing.T) {
 	})
 }
 
+func TestAccBrightboxServer_DefaultZone(t *testing.T) {
+	resourceName := "brightbox_server.foobar"
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(),
+		CheckDestroy:      testAccCheckBrightboxServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxServerConfig_defaultZone(rInt, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "zone", "gb1-b"),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxServerConfig_defaultZone(rInt, `zone = "gb1-a"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "zone", "gb1-a"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBrightboxServer_Snapshots(t *testing.T) {
 	resourceName := "brightbox_server.foobar"
 	var server brightbox.Server
@@ -804,6 +1024,52 @@ data "brightbox_server_group" "barfoo" {
 		TestAccBrightboxDataServerGroupConfig_default)
 }
 
+func testAccCheckBrightboxServerConfig_writeOnlyUserData(rInt int, userData string, version int) string {
+	return fmt.Sprintf(`
+resource "brightbox_server" "foobar" {
+	image = data.brightbox_image.foobar.id
+	name = "foo-%d"
+	type = "1gb.ssd"
+	server_groups = [data.brightbox_server_group.default.id]
+	user_data_wo = %q
+	user_data_wo_version = %d
+}
+
+%s%s`, rInt, userData, version, TestAccBrightboxImageDataSourceConfig_blank_disk,
+		TestAccBrightboxDataServerGroupConfig_default)
+}
+
+func testAccCheckBrightboxServerConfig_powerState(rInt int, powerState string) string {
+	return fmt.Sprintf(`
+resource "brightbox_server" "foobar" {
+	image = data.brightbox_image.foobar.id
+	name = "foo-%d"
+	type = "1gb.ssd"
+	server_groups = [data.brightbox_server_group.default.id]
+	power_state = %q
+	force_stop = true
+}
+
+%s%s`, rInt, powerState, TestAccBrightboxImageDataSourceConfig_blank_disk,
+		TestAccBrightboxDataServerGroupConfig_default)
+}
+
+func testAccCheckBrightboxServerConfig_defaultZone(rInt int, zone string) string {
+	return fmt.Sprintf(`
+provider "brightbox" {
+	default_zone = "gb1-b"
+}
+
+resource "brightbox_server" "foobar" {
+	image = data.brightbox_image.foobar.id
+	name = "foo-%d"
+	type = "1gb.ssd"
+	%s
+}
+
+%s`, rInt, zone, TestAccBrightboxImageDataSourceConfig_blank_disk)
+}
+
 func testAccCheckBrightboxServerConfig_noUserData(rInt int) string {
 	return fmt.Sprintf(`
 resource "brightbox_server" "foobar" {
@@ -1059,7 +1325,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.Servers(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.Servers(ctx)
 			if err != nil {
 				var apierror *brightbox.APIError
 				if errors.As(err, &apierror) {
@@ -1077,10 +1347,10 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.UnlockServer(ctx, object.ID); err != nil {
+					if _, err := apiClient.UnlockServer(ctx, object.ID); err != nil {
 						log.Printf("error unlocking %s during sweep: %s", object.ID, err)
 					}
-					if _, err := client.APIClient.DestroyServer(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyServer(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
@@ -2,13 +2,13 @@ package brightbox
 
 import (
 	"context"
-	"log"
 	"regexp"
 	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
 	"github.com/brightbox/gobrightbox/v2/enums/filesystemtype"
 	"github.com/brightbox/gobrightbox/v2/enums/volumestatus"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -22,12 +22,12 @@ func resourceBrightboxVolume() *schema.Resource {
 		ReadContext:   resourceBrightboxVolumeRead,
 		UpdateContext: resourceBrightboxVolumeUpdateAndResize,
 		DeleteContext: resourceBrightboxVolumeDetachAndDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer:      idImporter(),
+		Identity:      idIdentity(),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
 			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
@@ -182,9 +182,12 @@ func resourceBrightboxVolumeDetachAndDelete(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	err := detachVolume(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))
+	ctx = NewLogContext(ctx, "Volume", d.Id(), "delete")
+	err := detachVolume(ctx, d, meta, resourceTimeout(d, meta, schema.TimeoutDelete))
 	if err != nil {
-		log.Printf("[INFO] Detach on delete issue: %s", err)
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Detach on delete issue", map[string]interface{}{
+			"error": err.Error(),
+		})
 	}
 	return resourceBrightboxVolumeDelete(ctx, d, meta)
 }
@@ -225,6 +228,7 @@ func setVolumeAttributes(
 	var err error
 
 	d.SetId(volume.ID)
+	diags = append(diags, setIDIdentity(d)...)
 	err = d.Set("name", volume.Name)
 	if err != nil {
 		diags = append(diags, diag.Errorf("unexpected: %s", err)...)
@@ -302,7 +306,9 @@ func volumeStateRefresh(client *brightbox.Client, ctx context.Context, volumeID
 	return func() (interface{}, string, error) {
 		volume, err := client.Volume(ctx, volumeID)
 		if err != nil {
-			log.Printf("Error on Volume State Refresh: %s", err)
+			tflog.SubsystemDebug(ctx, logSubsystemAPI, "Error on Volume State Refresh", map[string]interface{}{
+				"error": err.Error(),
+			})
 			return nil, "", err
 		}
 		return volume, volume.Status.String(), nil
@@ -314,9 +320,13 @@ func resourceBrightboxVolumeCreateAndWait(
 	d *schema.ResourceData,
 	meta interface{},
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	ctx = NewLogContext(ctx, "Volume", "", "create")
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 
-	log.Printf("[INFO] Creating volume")
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Creating volume")
 	var volumeOpts brightbox.VolumeOptions
 
 	var diags diag.Diagnostics
@@ -326,7 +336,9 @@ func resourceBrightboxVolumeCreateAndWait(
 		return diags
 	}
 
-	log.Printf("[DEBUG] volume create configuration: %+v", volumeOpts)
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Volume create configuration", map[string]interface{}{
+		logFieldOptions: redacted(volumeOpts),
+	})
 
 	object, err := client.CreateVolume(ctx, volumeOpts)
 	if err != nil {
@@ -334,8 +346,9 @@ func resourceBrightboxVolumeCreateAndWait(
 	}
 
 	d.SetId(object.ID)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldObjectID, d.Id())
 
-	log.Printf("[INFO] Waiting for Volume (%s) to become available", d.Id())
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Waiting for Volume to become available")
 
 	stateConf := retry.StateChangeConf{
 		Pending: []string{
@@ -345,7 +358,7 @@ func resourceBrightboxVolumeCreateAndWait(
 			volumestatus.Detached.String(),
 		},
 		Refresh:    volumeStateRefresh(client, ctx, object.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Timeout:    resourceTimeout(d, meta, schema.TimeoutCreate),
 		Delay:      checkDelay,
 		MinTimeout: minimumRefreshWait,
 	}
@@ -356,7 +369,7 @@ func resourceBrightboxVolumeCreateAndWait(
 
 	if serverID, ok := d.GetOk("server"); ok {
 		if target := serverID.(string); target != "" {
-			if err := attachVolume(ctx, d, meta, target, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			if err := attachVolume(ctx, d, meta, target, resourceTimeout(d, meta, schema.TimeoutUpdate)); err != nil {
 				diags = append(diags, brightboxFromErr(err))
 			}
 		}
@@ -371,26 +384,27 @@ func resourceBrightboxVolumeUpdateAndResize(
 	meta interface{},
 ) diag.Diagnostics {
 	var diags diag.Diagnostics
-	log.Printf("[DEBUG] Checking if volume size has changed")
+	ctx = NewLogContext(ctx, "Volume", d.Id(), "update")
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Checking if volume size has changed")
 	if d.HasChange("size") {
 		diags = append(diags, resizeBrightboxVolume(ctx, d, meta, d.Id(), "size")...)
 	}
-	log.Printf("[DEBUG] Checking if server attachment has changed")
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Checking if server attachment has changed")
 	if d.HasChange("server") {
-		log.Printf("[INFO] Volume server attachment has changed, updating...")
-		err := detachVolume(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))
+		tflog.SubsystemInfo(ctx, logSubsystemAPI, "Volume server attachment has changed, updating")
+		err := detachVolume(ctx, d, meta, resourceTimeout(d, meta, schema.TimeoutUpdate))
 		if err != nil {
 			diags = append(diags, brightboxFromErr(err))
 		} else if serverID, ok := d.GetOk("server"); ok {
 			if target := serverID.(string); target != "" {
-				err := attachVolume(ctx, d, meta, target, d.Timeout(schema.TimeoutUpdate))
+				err := attachVolume(ctx, d, meta, target, resourceTimeout(d, meta, schema.TimeoutUpdate))
 				if err != nil {
 					diags = append(diags, brightboxFromErr(err))
 				}
 			}
 		}
 	}
-	log.Printf("[DEBUG] Updating volume")
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Updating volume")
 	return append(diags, resourceBrightboxVolumeUpdate(ctx, d, meta)...)
 }
 
@@ -401,9 +415,15 @@ func attachVolume(
 	server string,
 	timeout time.Duration,
 ) error {
-	log.Printf("[DEBUG] attaching %v to %v", d.Id(), server)
-	client := meta.(*CompositeClient).APIClient
-	_, err := client.AttachVolume(
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Attaching volume", map[string]interface{}{
+		logFieldObjectID: d.Id(),
+		"server":         server,
+	})
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.AttachVolume(
 		ctx,
 		d.Id(),
 		brightbox.VolumeAttachment{Server: server},
@@ -433,9 +453,14 @@ func detachVolume(
 	meta interface{},
 	timeout time.Duration,
 ) error {
-	log.Printf("[DEBUG] detaching %v", d.Id())
-	client := meta.(*CompositeClient).APIClient
-	_, err := client.DetachVolume(
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Detaching volume", map[string]interface{}{
+		logFieldObjectID: d.Id(),
+	})
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.DetachVolume(
 		ctx,
 		d.Id(),
 	)
@@ -465,10 +490,13 @@ func resizeBrightboxVolume(
 	volumeID string,
 	sizeAttr string,
 ) diag.Diagnostics {
-	client := meta.(*CompositeClient).APIClient
+	client, err := meta.(*CompositeClient).APIClient()
+	if err != nil {
+		return brightboxFromErrSlice(err)
+	}
 	var diags diag.Diagnostics
 
-	log.Printf("[DEBUG] Checking size change parameters")
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Checking size change parameters")
 	oldSize, newSize := d.GetChange(sizeAttr)
 	oldSizeInt, ok := oldSize.(int)
 	if !ok {
@@ -485,8 +513,12 @@ func resizeBrightboxVolume(
 	if diags.HasError() {
 		return diags
 	}
-	log.Printf("[INFO] Resizing volume %v from %v to %v", volumeID, oldSizeInt, newSizeInt)
-	_, err := client.ResizeVolume(
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Resizing volume", map[string]interface{}{
+		"volume_id": volumeID,
+		"from":      oldSizeInt,
+		"to":        newSizeInt,
+	})
+	_, err = client.ResizeVolume(
 		ctx,
 		volumeID,
 		brightbox.VolumeNewSize{
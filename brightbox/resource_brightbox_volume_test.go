@@ -482,7 +482,10 @@ func testAccCheckBrightboxVolumeAttributes(volume *brightbox.Volume) resource.Te
 }
 
 func testAccCheckBrightboxVolumeDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*CompositeClient).APIClient
+	client, err := testAccProvider.Meta().(*CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "brightbox_volume" {
@@ -523,7 +526,11 @@ func init() {
 			if errs != nil {
 				return fmt.Errorf("%s", errs[0].Summary)
 			}
-			objects, err := client.APIClient.Volumes(ctx)
+			apiClient, err := client.APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.Volumes(ctx)
 			if err != nil {
 				return err
 			}
@@ -533,10 +540,10 @@ func init() {
 				}
 				if isTestName(object.Name) {
 					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
-					if _, err := client.APIClient.UnlockVolume(ctx, object.ID); err != nil {
+					if _, err := apiClient.UnlockVolume(ctx, object.ID); err != nil {
 						log.Printf("error unlocking %s during sweep: %s", object.ID, err)
 					}
-					if _, err := client.APIClient.DestroyVolume(ctx, object.ID); err != nil {
+					if _, err := apiClient.DestroyVolume(ctx, object.ID); err != nil {
 						log.Printf("error destroying %s during sweep: %s", object.ID, err)
 					}
 				}
@@ -0,0 +1,97 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"time"
+
+	brightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/brightbox/gobrightbox/v2/enums/serverstatus"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// snapshotLinkRegexp finds the new image in the Link header of a
+// snapshot response
+var snapshotLinkRegexp = regexp.MustCompile(`/images/(img-[0-9a-z]{5})>`)
+
+// WaitForServerStatus waits until the server reaches target, polling
+// the same way as the server resource while the server is in any of
+// the pending states
+func WaitForServerStatus(
+	ctx context.Context,
+	client *brightbox.Client,
+	serverID string,
+	pending []serverstatus.Enum,
+	target serverstatus.Enum,
+	timeout time.Duration,
+) (*brightbox.Server, error) {
+	pendingStates := make([]string, 0, len(pending))
+	for _, status := range pending {
+		pendingStates = append(pendingStates, status.String())
+	}
+	tflog.SubsystemInfo(ctx, logSubsystemAPI, "Waiting for Server to become "+target.String())
+	stateConf := retry.StateChangeConf{
+		Pending:    pendingStates,
+		Target:     []string{target.String()},
+		Refresh:    serverStateRefresh(client, ctx, serverID),
+		Timeout:    timeout,
+		Delay:      checkDelay,
+		MinTimeout: minimumRefreshWait,
+	}
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*brightbox.Server), nil
+}
+
+// SnapshotServer snapshots the disk of a server, returning the ID of
+// the new image when the API reports it. The API library has no call
+// for this, so the request is made with the client's own HTTP client.
+func SnapshotServer(ctx context.Context, client *brightbox.Client, serverID string) (string, error) {
+	snapshotURL, err := client.ResourceBaseURL().Parse(path.Join("servers", serverID, "snapshot"))
+	if err != nil {
+		return "", err
+	}
+	snapshotURL.RawQuery = client.ResourceBaseURL().RawQuery
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, snapshotURL.String(), http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Accept", "application/json")
+	if client.UserAgent != "" {
+		req.Header.Add("User-Agent", client.UserAgent)
+	}
+	res, err := client.HTTPClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", snapshotError(res)
+	}
+	if match := snapshotLinkRegexp.FindStringSubmatch(res.Header.Get("Link")); match != nil {
+		return match[1], nil
+	}
+	return "", nil
+}
+
+// snapshotError describes a failed snapshot request the way the API
+// library describes its own failures
+func snapshotError(res *http.Response) error {
+	apiErr := &brightbox.APIError{
+		RequestURL: res.Request.URL,
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+	}
+	apiErr.ResponseBody, apiErr.ParseError = io.ReadAll(res.Body)
+	if len(apiErr.ResponseBody) > 0 {
+		apiErr.ParseError = json.Unmarshal(apiErr.ResponseBody, apiErr)
+	}
+	return apiErr
+}
@@ -0,0 +1,31 @@
+package brightbox
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSnapshotServer(t *testing.T) {
+	composite := newFakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/1.0/servers/srv-12345/snapshot" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_name":"missing_resource","errors":["Resource not found"]}`))
+			return
+		}
+		w.Header().Set("Link", "<https://api.gb1.brightbox.com/1.0/images/img-abcde>; rel=snapshot")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"srv-12345","status":"active"}`))
+	})
+	client, err := composite.APIClient()
+	assert.NilError(t, err)
+
+	image, err := SnapshotServer(context.Background(), client, "srv-12345")
+	assert.NilError(t, err)
+	assert.Equal(t, image, "img-abcde")
+
+	_, err = SnapshotServer(context.Background(), client, "srv-54321")
+	assert.ErrorContains(t, err, "missing_resource: Resource not found")
+}
@@ -0,0 +1,283 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/brightbox/gobrightbox/v2/endpoint"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+	oauth2cc "golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	tokenCacheFileName = "terraform-token-cache.json"
+	tokenCacheFileMode = 0600
+	tokenCacheDirMode  = 0700
+)
+
+// tokenCacheLock serialises access to token cache files within the
+// provider process
+var tokenCacheLock sync.Mutex
+
+// tokenCache stores OAuth tokens in a JSON file readable only by the
+// current user, keyed by the API endpoint and credentials they were
+// issued for.
+type tokenCache struct {
+	path string
+}
+
+func defaultTokenCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configDirName, tokenCacheFileName), nil
+}
+
+func newTokenCache(path string) (*tokenCache, error) {
+	if path == "" {
+		var err error
+		path, err = defaultTokenCachePath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &tokenCache{path: path}, nil
+}
+
+// tokenCacheKey identifies the tokens issued for a set of credentials
+func tokenCacheKey(authd authdetails) string {
+	apiURL := authd.APIURL
+	if apiURL == "" {
+		apiURL = endpoint.DefaultBaseURL
+	}
+	return strings.Join(
+		[]string{strings.TrimSuffix(apiURL, "/"), authd.APIClient, authd.Account, authd.UserName},
+		"|",
+	)
+}
+
+func (c *tokenCache) readAll() (map[string]*oauth2.Token, error) {
+	entries := make(map[string]*oauth2.Token)
+	info, err := os.Stat(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("token cache %s is accessible by other users (mode %v)", c.path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("token cache %s is corrupt: %w", c.path, err)
+	}
+	return entries, nil
+}
+
+// Load returns the cached token for key, or nil if there isn't one
+func (c *tokenCache) Load(key string) (*oauth2.Token, error) {
+	tokenCacheLock.Lock()
+	defer tokenCacheLock.Unlock()
+	entries, err := c.readAll()
+	if err != nil {
+		return nil, err
+	}
+	return entries[key], nil
+}
+
+// Store saves the token under key, replacing the cache file atomically
+func (c *tokenCache) Store(ctx context.Context, key string, token *oauth2.Token) error {
+	tokenCacheLock.Lock()
+	defer tokenCacheLock.Unlock()
+	entries, err := c.readAll()
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystemAuth, "Discarding unusable token cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+		entries = make(map[string]*oauth2.Token)
+	}
+	entries[key] = token
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, tokenCacheDirMode); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, tokenCacheFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(tokenCacheFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// cachingTokenSource saves every new token obtained from the
+// underlying source into the cache
+type cachingTokenSource struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	base   oauth2.TokenSource
+	cache  *tokenCache
+	key    string
+	latest string
+}
+
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.AccessToken != s.latest {
+		tflog.SubsystemDebug(s.ctx, logSubsystemAuth, "Saving new access token to cache", map[string]interface{}{
+			"path": s.cache.path,
+		})
+		if err := s.cache.Store(s.ctx, s.key, token); err != nil {
+			tflog.SubsystemWarn(s.ctx, logSubsystemAuth, "Unable to save token to cache", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		s.latest = token.AccessToken
+	}
+	return token, nil
+}
+
+// passwordTokenSource obtains tokens with the password credentials
+// flow, using the refresh token from the previous token where possible
+// so the user only has to supply a one time code when it has expired.
+type passwordTokenSource struct {
+	ctx      context.Context
+	conf     *oauth2.Config
+	userName string
+	password string
+	current  *oauth2.Token
+}
+
+func (s *passwordTokenSource) Token() (*oauth2.Token, error) {
+	if s.current != nil && s.current.RefreshToken != "" {
+		tflog.SubsystemDebug(s.ctx, logSubsystemAuth, "Refreshing access token")
+		token, err := s.conf.TokenSource(
+			s.ctx,
+			&oauth2.Token{RefreshToken: s.current.RefreshToken},
+		).Token()
+		if err == nil {
+			s.current = token
+			return token, nil
+		}
+		tflog.SubsystemInfo(s.ctx, logSubsystemAuth, "Unable to refresh access token", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if s.password == "" {
+		return nil, errors.New("No valid cached token is available. Please supply a password and One Time Authentication code")
+	}
+	tflog.SubsystemDebug(s.ctx, logSubsystemAuth, "Requesting access token with user credentials")
+	token, err := s.conf.PasswordCredentialsToken(s.ctx, s.userName, s.password)
+	if err != nil {
+		return nil, err
+	}
+	s.current = token
+	return token, nil
+}
+
+// cachedCredentials implements the brightbox.Oauth2 access interface,
+// seeding the token source from the token cache and saving any new
+// tokens back to it.
+type cachedCredentials struct {
+	endpoint.Config
+	authd authdetails
+	cache *tokenCache
+}
+
+func (c *cachedCredentials) Client(ctx context.Context) (*http.Client, oauth2.TokenSource, error) {
+	key := tokenCacheKey(c.authd)
+	seed, err := c.cache.Load(key)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystemAuth, "Ignoring token cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+		seed = nil
+	}
+	base, err := c.tokenSource(ctx, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	var latest string
+	if seed != nil {
+		tflog.SubsystemDebug(ctx, logSubsystemAuth, "Found cached token", map[string]interface{}{
+			"cache_key": key,
+		})
+		latest = seed.AccessToken
+	}
+	ts := &cachingTokenSource{
+		ctx:    ctx,
+		base:   oauth2.ReuseTokenSource(seed, base),
+		cache:  c.cache,
+		key:    key,
+		latest: latest,
+	}
+	if _, err := ts.Token(); err != nil {
+		return nil, nil, err
+	}
+	return oauth2.NewClient(ctx, ts), ts, nil
+}
+
+func (c *cachedCredentials) tokenSource(ctx context.Context, seed *oauth2.Token) (oauth2.TokenSource, error) {
+	if c.authd.UserName != "" {
+		oauthEndpoint, err := c.Endpoint()
+		if err != nil {
+			return nil, err
+		}
+		return &passwordTokenSource{
+			ctx: ctx,
+			conf: &oauth2.Config{
+				ClientID:     c.authd.APIClient,
+				ClientSecret: c.authd.APISecret,
+				Scopes:       c.Scopes,
+				Endpoint:     *oauthEndpoint,
+			},
+			userName: c.authd.UserName,
+			password: c.authd.password,
+			current:  seed,
+		}, nil
+	}
+	tokenURL, err := c.TokenURL()
+	if err != nil {
+		return nil, err
+	}
+	conf := &oauth2cc.Config{
+		ClientID:     c.authd.APIClient,
+		ClientSecret: c.authd.APISecret,
+		Scopes:       c.Scopes,
+		TokenURL:     tokenURL,
+	}
+	return conf.TokenSource(ctx), nil
+}
@@ -0,0 +1,174 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gotest.tools/v3/assert"
+)
+
+// fakeTokenServer issues numbered tokens, counting how each was
+// obtained
+type fakeTokenServer struct {
+	*httptest.Server
+	issued    int32
+	passwords int32
+	refreshes int32
+	expiresIn int
+}
+
+func newFakeTokenServer(t *testing.T) *fakeTokenServer {
+	f := &fakeTokenServer{expiresIn: 3600}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token/" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.PostForm.Get("grant_type") {
+		case "password":
+			atomic.AddInt32(&f.passwords, 1)
+		case "refresh_token":
+			atomic.AddInt32(&f.refreshes, 1)
+		}
+		count := atomic.AddInt32(&f.issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", count),
+			"refresh_token": fmt.Sprintf("refresh-%d", count),
+			"token_type":    "Bearer",
+			"expires_in":    f.expiresIn,
+		})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestTokenCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", tokenCacheFileName)
+	cache, err := newTokenCache(path)
+	assert.NilError(t, err)
+
+	token, err := cache.Load("missing")
+	assert.NilError(t, err)
+	assert.Assert(t, token == nil)
+
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+	assert.NilError(t, cache.Store(context.Background(), "key", &oauth2.Token{AccessToken: "abc", RefreshToken: "def", Expiry: expiry}))
+	info, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(tokenCacheFileMode))
+
+	token, err = cache.Load("key")
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "abc")
+	assert.Equal(t, token.RefreshToken, "def")
+	assert.Assert(t, token.Expiry.Equal(expiry))
+}
+
+func TestTokenCacheRejectsSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), tokenCacheFileName)
+	assert.NilError(t, os.WriteFile(path, []byte(`{}`), 0644))
+	cache, err := newTokenCache(path)
+	assert.NilError(t, err)
+	_, err = cache.Load("key")
+	assert.ErrorContains(t, err, "accessible by other users")
+}
+
+func TestCachedCredentialsReuseTokens(t *testing.T) {
+	server := newFakeTokenServer(t)
+	authd := authdetails{
+		APIClient:      "app-12345",
+		APISecret:      "secret",
+		UserName:       "fred@example.com",
+		password:       "password123456",
+		Account:        "acc-12345",
+		APIURL:         server.URL,
+		TokenCache:     true,
+		TokenCacheFile: filepath.Join(t.TempDir(), tokenCacheFileName),
+	}
+	ctx := context.Background()
+
+	conf, err := confFromAuthd(context.Background(), authd)
+	assert.NilError(t, err)
+	_, ts, err := conf.Client(ctx)
+	assert.NilError(t, err)
+	token, err := ts.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "access-1")
+	assert.Equal(t, server.passwords, int32(1))
+
+	// A second run with no one time code picks up the cached token
+	authd.password = ""
+	conf, err = confFromAuthd(context.Background(), authd)
+	assert.NilError(t, err)
+	_, ts, err = conf.Client(ctx)
+	assert.NilError(t, err)
+	token, err = ts.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "access-1")
+	assert.Equal(t, server.issued, int32(1))
+}
+
+func TestCachedCredentialsRefreshExpiredToken(t *testing.T) {
+	server := newFakeTokenServer(t)
+	cacheFile := filepath.Join(t.TempDir(), tokenCacheFileName)
+	authd := authdetails{
+		APIClient:      "app-12345",
+		APISecret:      "secret",
+		UserName:       "fred@example.com",
+		Account:        "acc-12345",
+		APIURL:         server.URL,
+		TokenCache:     true,
+		TokenCacheFile: cacheFile,
+	}
+	cache, err := newTokenCache(cacheFile)
+	assert.NilError(t, err)
+	assert.NilError(t, cache.Store(context.Background(), tokenCacheKey(authd), &oauth2.Token{
+		AccessToken:  "stale",
+		RefreshToken: "refresh-0",
+		Expiry:       time.Now().Add(-time.Minute),
+	}))
+
+	conf, err := confFromAuthd(context.Background(), authd)
+	assert.NilError(t, err)
+	_, ts, err := conf.Client(context.Background())
+	assert.NilError(t, err)
+	token, err := ts.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "access-1")
+	assert.Equal(t, server.refreshes, int32(1))
+	assert.Equal(t, server.passwords, int32(0))
+
+	cached, err := cache.Load(tokenCacheKey(authd))
+	assert.NilError(t, err)
+	assert.Equal(t, cached.AccessToken, "access-1")
+}
+
+func TestCachedCredentialsNeedPasswordWithoutCache(t *testing.T) {
+	server := newFakeTokenServer(t)
+	conf, err := confFromAuthd(context.Background(), authdetails{
+		APIClient:      "app-12345",
+		APISecret:      "secret",
+		UserName:       "fred@example.com",
+		Account:        "acc-12345",
+		APIURL:         server.URL,
+		TokenCache:     true,
+		TokenCacheFile: filepath.Join(t.TempDir(), tokenCacheFileName),
+	})
+	assert.NilError(t, err)
+	_, _, err = conf.Client(context.Background())
+	assert.ErrorContains(t, err, "No valid cached token is available")
+}
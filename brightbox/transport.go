@@ -0,0 +1,425 @@
+package brightbox
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"golang.org/x/time/rate"
+)
+
+const (
+	providerName        = "terraform-provider-brightbox"
+	defaultMaxRetries   = 3
+	defaultRetryMaxWait = 30
+	retryMinWait        = 1 * time.Second
+)
+
+// limiterLogThreshold is the shortest limiter wait worth logging
+const limiterLogThreshold = 10 * time.Millisecond
+
+// transportdetails configures the HTTP client shared by the API,
+// Orbit and OAuth requests
+type transportdetails struct {
+	MaxRetries            int
+	RetryMaxWait          time.Duration
+	MaxRequestsPerSecond  float64
+	MaxConcurrentRequests int
+	HTTPProxy             string
+	CABundle              string
+	Insecure              bool
+	ReadOnly              bool
+	TokenURL              string
+	UserAgent             string
+}
+
+// newHTTPClient builds the HTTP client used for all Brightbox traffic
+// from a single provider instance
+func newHTTPClient(ctx context.Context, td transportdetails) (*http.Client, diag.Diagnostics) {
+	client := cleanhttp.DefaultPooledClient()
+	base := client.Transport.(*http.Transport)
+	diags := configureBaseTransport(ctx, base, td)
+	if diags.HasError() {
+		return nil, diags
+	}
+	var transport http.RoundTripper = base
+	if httpTraceEnabled() {
+		tflog.Debug(ctx, "Enabling HTTP request and response tracing")
+		transport = &logTransport{base: transport}
+	}
+	if td.UserAgent != "" {
+		transport = &userAgentTransport{base: transport, userAgent: td.UserAgent}
+	}
+	if td.MaxRequestsPerSecond > 0 || td.MaxConcurrentRequests > 0 {
+		transport = newLimitTransport(transport, td.MaxRequestsPerSecond, td.MaxConcurrentRequests)
+	}
+	if td.MaxRetries > 0 {
+		transport = &retryTransport{
+			base:       transport,
+			maxRetries: td.MaxRetries,
+			minWait:    retryMinWait,
+			maxWait:    td.RetryMaxWait,
+		}
+	}
+	if td.ReadOnly {
+		tflog.Info(ctx, "Read only mode: only read requests will be sent")
+		transport = &readOnlyTransport{base: transport, tokenURL: td.TokenURL}
+	}
+	client.Transport = transport
+	return client, diags
+}
+
+// configureBaseTransport applies the proxy and TLS settings to the
+// underlying transport
+func configureBaseTransport(ctx context.Context, base *http.Transport, td transportdetails) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if td.HTTPProxy != "" {
+		proxyURL, err := url.Parse(td.HTTPProxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return diag.Errorf("Invalid http_proxy %q: expected a URL such as http://proxy.example.com:3128", redactURL(td.HTTPProxy))
+		}
+		tflog.Debug(ctx, "Using HTTP proxy", map[string]interface{}{"proxy": proxyURL.Redacted()})
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+	if td.CABundle != "" {
+		pool, err := loadCABundle(ctx, td.CABundle)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		base.TLSClientConfig = tlsConfig(base)
+		base.TLSClientConfig.RootCAs = pool
+	}
+	if td.Insecure {
+		tflog.Warn(ctx, "TLS certificate verification disabled")
+		base.TLSClientConfig = tlsConfig(base)
+		base.TLSClientConfig.InsecureSkipVerify = true
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "TLS certificate verification is disabled",
+			Detail: "The provider is configured with insecure = true, so it will not verify the identity of the Brightbox API, " +
+				"Orbit or the token endpoint. Credentials and tokens can be intercepted. " +
+				"Only use this setting for testing.",
+		})
+	}
+	return diags
+}
+
+func tlsConfig(base *http.Transport) *tls.Config {
+	if base.TLSClientConfig != nil {
+		return base.TLSClientConfig
+	}
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
+
+// loadCABundle adds the certificates in bundle to the system pool.
+// The bundle is either PEM content or the path to a PEM file.
+func loadCABundle(ctx context.Context, bundle string) (*x509.CertPool, error) {
+	pem := []byte(bundle)
+	source := "ca_bundle"
+	if !strings.Contains(bundle, "-----BEGIN") {
+		var err error
+		pem, err = os.ReadFile(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read ca_bundle: %w", err)
+		}
+		source = bundle
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		tflog.Debug(ctx, "System certificate pool unavailable", map[string]interface{}{"error": err.Error()})
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("No PEM encoded certificates found in %s", source)
+	}
+	return pool, nil
+}
+
+// redactURL hides any password in a URL that may not parse
+func redactURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Redacted()
+	}
+	return "(unparseable URL)"
+}
+
+// userAgent identifies the provider and Terraform releases making a
+// request, followed by any suffix the user has configured
+func userAgent(providerVersion string, terraformVersion string, suffix string) string {
+	if providerVersion == "" {
+		providerVersion = "dev"
+	}
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+	result := fmt.Sprintf("%s/%s terraform/%s", providerName, providerVersion, terraformVersion)
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		result += " " + suffix
+	}
+	return result
+}
+
+// userAgentTransport sets the User-Agent on every request, replacing
+// any set by the API, Orbit or OAuth libraries
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
+
+// limitTransport throttles requests with a token bucket and caps the
+// number in flight at once. Retries pass through the limiter too, so
+// they count against the same budget as the requests they replace.
+type limitTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+// newLimitTransport builds a limiter allowing rps requests a second
+// and at most concurrent requests in flight. A zero value for either
+// disables that limit.
+func newLimitTransport(base http.RoundTripper, rps float64, concurrent int) *limitTransport {
+	t := &limitTransport{base: base}
+	if rps > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(rps), int(math.Max(1, math.Ceil(rps))))
+	}
+	if concurrent > 0 {
+		t.slots = make(chan struct{}, concurrent)
+	}
+	return t
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+	if wait := time.Since(start); wait >= limiterLogThreshold {
+		tflog.SubsystemDebug(ctx, requestLogSubsystem(req), "Request waited in the request limiter", map[string]interface{}{
+			"http_method": req.Method,
+			"http_url":    req.URL.Redacted(),
+			"wait":        wait.String(),
+		})
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		t.release()
+		return resp, err
+	}
+	// Hold the slot until the response has been read
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+func (t *limitTransport) release() {
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// releasingBody frees a concurrency slot when the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// retryTransport retries throttled and transiently failing requests
+// with exponential backoff and jitter.
+//
+// Idempotent requests are retried on connection errors and on 429,
+// 502, 503 and 504 responses. Other requests, such as POST, are only
+// retried when the API has marked the failure as safe to retry: a 429
+// response, or a 502/503 response carrying a Retry-After header,
+// either of which means the request was rejected before it was
+// processed.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	minWait    time.Duration
+	maxWait    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !rewindable(req) {
+			return resp, err
+		}
+		wait, ok := t.retryDelay(req, resp, err, attempt)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			tflog.SubsystemInfo(req.Context(), requestLogSubsystem(req), "Retrying request", map[string]interface{}{
+				"http_method":      req.Method,
+				"http_url":         req.URL.Redacted(),
+				"http_status_code": resp.StatusCode,
+				"wait":             wait.String(),
+			})
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			tflog.SubsystemInfo(req.Context(), requestLogSubsystem(req), "Retrying failed request", map[string]interface{}{
+				"http_method": req.Method,
+				"http_url":    req.URL.Redacted(),
+				"error":       err.Error(),
+				"wait":        wait.String(),
+			})
+		}
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		attemptReq, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether a request should be retried, and if so
+// how long to wait before doing so
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		if req.Context().Err() != nil || !idempotent(req.Method) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		if !idempotent(req.Method) && resp.Header.Get("Retry-After") == "" {
+			return 0, false
+		}
+	case http.StatusGatewayTimeout:
+		if !idempotent(req.Method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if wait, ok := retryAfter(resp); ok {
+		if wait > t.maxWait {
+			tflog.SubsystemWarn(req.Context(), requestLogSubsystem(req), "Retry-After is longer than the maximum wait, waiting the maximum instead", map[string]interface{}{
+				"http_method": req.Method,
+				"http_url":    req.URL.Redacted(),
+				"retry_after": wait.String(),
+				"max_wait":    t.maxWait.String(),
+			})
+			return t.maxWait, true
+		}
+		return wait, true
+	}
+	return t.backoff(attempt), true
+}
+
+// backoff doubles the wait on each attempt up to the maximum, then
+// picks a random point in the upper half of that range
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	ceiling := t.minWait << uint(attempt)
+	if ceiling <= 0 || ceiling > t.maxWait {
+		ceiling = t.maxWait
+	}
+	half := ceiling / 2
+	if half <= 0 {
+		return ceiling
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// retryAfter parses the Retry-After header in either of its seconds
+// or HTTP date forms
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	result := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		result.Body = body
+	}
+	return result, nil
+}
+
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
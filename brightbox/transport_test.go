@@ -0,0 +1,287 @@
+package brightbox
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"gotest.tools/v3/assert"
+)
+
+// failingServer fails the first n requests with the given status
+// and headers, then succeeds, echoing back the request body
+func failingServer(t *testing.T, n int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) <= n {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testRetryClient() *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			base:       http.DefaultTransport,
+			maxRetries: 3,
+			minWait:    time.Millisecond,
+			maxWait:    10 * time.Millisecond,
+		},
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		failures int32
+		status   int
+		header   http.Header
+		result   int
+		calls    int32
+	}{
+		{"get recovers from 503", http.MethodGet, 2, http.StatusServiceUnavailable, nil, http.StatusOK, 3},
+		{"delete recovers from 502", http.MethodDelete, 1, http.StatusBadGateway, nil, http.StatusOK, 2},
+		{"get gives up after max retries", http.MethodGet, 10, http.StatusServiceUnavailable, nil, http.StatusServiceUnavailable, 4},
+		{"post is not replayed after 503", http.MethodPost, 1, http.StatusServiceUnavailable, nil, http.StatusServiceUnavailable, 1},
+		{"post is not replayed after 504", http.MethodPost, 1, http.StatusGatewayTimeout, nil, http.StatusGatewayTimeout, 1},
+		{"post is replayed after 429", http.MethodPost, 1, http.StatusTooManyRequests, nil, http.StatusOK, 2},
+		{"post is replayed after 503 with Retry-After", http.MethodPost, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"0"}}, http.StatusOK, 2},
+		{"Retry-After beyond maximum wait", http.MethodGet, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"120"}}, http.StatusOK, 2},
+		{"client errors are not retried", http.MethodGet, 1, http.StatusNotFound, nil, http.StatusNotFound, 1},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			server, calls := failingServer(t, tcase.failures, tcase.status, tcase.header)
+			req, err := http.NewRequest(tcase.method, server.URL, strings.NewReader("payload"))
+			assert.NilError(t, err)
+			resp, err := testRetryClient().Do(req)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tcase.result)
+			assert.Equal(t, atomic.LoadInt32(calls), tcase.calls)
+			if resp.StatusCode == http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				assert.NilError(t, err)
+				assert.Equal(t, string(body), "payload")
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := retryAfter(resp)
+	assert.Assert(t, !ok)
+
+	resp.Header.Set("Retry-After", "5")
+	wait, ok := retryAfter(resp)
+	assert.Assert(t, ok)
+	assert.Equal(t, wait, 5*time.Second)
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	wait, ok = retryAfter(resp)
+	assert.Assert(t, ok)
+	assert.Equal(t, wait, time.Duration(0))
+}
+
+func TestRetryDelayCapsRetryAfter(t *testing.T) {
+	rt := &retryTransport{minWait: time.Second, maxWait: 8 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/1.0/servers", nil)
+	assert.NilError(t, err)
+	testCases := map[string]time.Duration{
+		"5":   5 * time.Second,
+		"120": 8 * time.Second,
+	}
+	for header, expected := range testCases {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": {header}},
+		}
+		wait, ok := rt.retryDelay(req, resp, nil, 0)
+		assert.Assert(t, ok, header)
+		assert.Equal(t, wait, expected, header)
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	rt := &retryTransport{minWait: time.Second, maxWait: 8 * time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := rt.backoff(attempt)
+		assert.Assert(t, wait >= 500*time.Millisecond, "attempt %d waited %v", attempt, wait)
+		assert.Assert(t, wait <= rt.maxWait, "attempt %d waited %v", attempt, wait)
+	}
+}
+
+func TestLimitTransportConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: newLimitTransport(http.DefaultTransport, 0, 2)}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Assert(t, atomic.LoadInt32(&peak) <= 2, "peak concurrency was %d", peak)
+}
+
+func TestLimitTransportRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: newLimitTransport(http.DefaultTransport, 20, 0)}
+	start := time.Now()
+	// The first 20 requests use the burst, the next 10 wait for tokens
+	for i := 0; i < 30; i++ {
+		resp, err := client.Get(server.URL)
+		assert.NilError(t, err)
+		resp.Body.Close()
+	}
+	assert.Assert(t, time.Since(start) >= 400*time.Millisecond, "30 requests took %v", time.Since(start))
+}
+
+func TestLimitTransportCancelledWait(t *testing.T) {
+	lt := newLimitTransport(http.DefaultTransport, 0, 1)
+	lt.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid", nil)
+	assert.NilError(t, err)
+	_, err = lt.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func serverCertPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestHTTPClientCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	bundleFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NilError(t, os.WriteFile(bundleFile, []byte(serverCertPEM(server)), 0600))
+
+	client, diags := newHTTPClient(context.Background(), transportdetails{})
+	assert.Assert(t, !diags.HasError())
+	_, err := client.Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	for _, bundle := range []string{serverCertPEM(server), bundleFile} {
+		client, diags := newHTTPClient(context.Background(), transportdetails{CABundle: bundle})
+		assert.Assert(t, !diags.HasError())
+		resp, err := client.Get(server.URL)
+		assert.NilError(t, err)
+		resp.Body.Close()
+	}
+}
+
+func TestHTTPClientBadCABundle(t *testing.T) {
+	_, diags := newHTTPClient(context.Background(), transportdetails{CABundle: "-----BEGIN CERTIFICATE-----\nnonsense"})
+	assert.Assert(t, diags.HasError())
+	assert.Equal(t, diags[0].Summary, "No PEM encoded certificates found in ca_bundle")
+
+	_, diags = newHTTPClient(context.Background(), transportdetails{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Assert(t, diags.HasError())
+	assert.Assert(t, strings.HasPrefix(diags[0].Summary, "Unable to read ca_bundle"), diags[0].Summary)
+}
+
+func TestHTTPClientInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	client, diags := newHTTPClient(context.Background(), transportdetails{Insecure: true})
+	assert.Assert(t, !diags.HasError())
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Severity, diag.Warning)
+	resp, err := client.Get(server.URL)
+	assert.NilError(t, err)
+	resp.Body.Close()
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	var proxied, proxyAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+	}))
+	t.Cleanup(proxy.Close)
+	proxyURL, err := url.Parse(proxy.URL)
+	assert.NilError(t, err)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	client, diags := newHTTPClient(context.Background(), transportdetails{HTTPProxy: proxyURL.String()})
+	assert.Assert(t, !diags.HasError())
+	resp, err := client.Get("http://api.example.invalid/1.0/servers")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, proxied, "http://api.example.invalid/1.0/servers")
+	assert.Equal(t, proxyAuth, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")))
+
+	_, diags = newHTTPClient(context.Background(), transportdetails{HTTPProxy: "proxy.example.com"})
+	assert.Assert(t, diags.HasError())
+}
+
+func TestUserAgent(t *testing.T) {
+	assert.Equal(t, userAgent("3.4.0", "1.9.2", ""), "terraform-provider-brightbox/3.4.0 terraform/1.9.2")
+	assert.Equal(t, userAgent("3.4.0", "1.9.2", " ci-pipeline/42 "), "terraform-provider-brightbox/3.4.0 terraform/1.9.2 ci-pipeline/42")
+	assert.Equal(t, userAgent("", "", ""), "terraform-provider-brightbox/dev terraform/unknown")
+}
+
+func TestHTTPClientUserAgent(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get("User-Agent")
+	}))
+	t.Cleanup(server.Close)
+
+	client, diags := newHTTPClient(context.Background(), transportdetails{UserAgent: "terraform-provider-brightbox/test terraform/1.9.2"})
+	assert.Assert(t, !diags.HasError())
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NilError(t, err)
+	req.Header.Set("User-Agent", "gophercloud/2.0.0")
+	resp, err := client.Do(req)
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, sent, "terraform-provider-brightbox/test terraform/1.9.2")
+	assert.Equal(t, req.Header.Get("User-Agent"), "gophercloud/2.0.0")
+}
@@ -5,23 +5,26 @@ import (
 	"encoding"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"hash/crc32"
 	"math"
 	"os"
 	"regexp"
 	"sort"
+	"strconv"
 	"time"
 
 	brightbox "github.com/brightbox/gobrightbox/v2"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gorhill/cronexpr"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var (
+	apiClientRegexp        = regexp.MustCompile("^cli-.....$")
+	cloudIPRegexp          = regexp.MustCompile("^cip-.....$")
 	serverRegexp           = regexp.MustCompile("^srv-.....$")
 	serverGroupRegexp      = regexp.MustCompile("^grp-.....$")
 	databaseTypeRegexp     = regexp.MustCompile("^dbt-.....$")
@@ -43,6 +46,51 @@ var (
 	validDatabaseEngines = []string{"mysql", "postgresql"}
 )
 
+// idTypes maps identifier patterns to the type of object they name
+var idTypes = []struct {
+	pattern *regexp.Regexp
+	name    string
+}{
+	{accountRegexp, "account"},
+	{apiClientRegexp, "api_client"},
+	{cloudIPRegexp, "cloud_ip"},
+	{databaseServerRegexp, "database_server"},
+	{databaseSnapshotRegexp, "database_snapshot"},
+	{databaseTypeRegexp, "database_type"},
+	{firewallPolicyRegexp, "firewall_policy"},
+	{firewallRuleRegexp, "firewall_rule"},
+	{imageRegexp, "image"},
+	{interfaceRegexp, "interface"},
+	{loadBalancerRegexp, "load_balancer"},
+	{serverRegexp, "server"},
+	{serverGroupRegexp, "server_group"},
+	{serverTypeRegexp, "server_type"},
+	{volumeRegexp, "volume"},
+	{zoneRegexp, "zone"},
+}
+
+// IDType returns the type of object a Brightbox identifier or zone
+// handle refers to, such as "server", or "" if it isn't recognised
+func IDType(id string) string {
+	for _, idType := range idTypes {
+		if idType.pattern.MatchString(id) {
+			return idType.name
+		}
+	}
+	return ""
+}
+
+// IPv6Hostname returns the name that resolves to the IPv6 address of
+// the server with the given FQDN
+func IPv6Hostname(fqdn string) string {
+	return "ipv6." + fqdn
+}
+
+// IsDNSName reports whether name is a valid fully qualified DNS name
+func IsDNSName(name string) bool {
+	return dnsNameRegexp.MatchString(name)
+}
+
 func timeFromFloat(timeFloat float64) time.Time {
 	sec, dec := math.Modf(timeFloat)
 	return time.Unix(int64(sec), int64(dec*(1e9)))
@@ -57,6 +105,12 @@ func hashString(
 	return ""
 }
 
+// UserDataHash returns the hash of user data recorded in state in
+// place of the data itself
+func UserDataHash(userData string) string {
+	return userDataHashSum(userData)
+}
+
 func userDataHashSum(userData string) string {
 	// Always calculate hash of base64 decoded value since we
 	// check against double-encoding when setting it
@@ -90,6 +144,35 @@ func assignString(d *schema.ResourceData, target **string, index string) {
 	}
 }
 
+// writeOnlyString returns the configured value of the write-only
+// attribute index, or "" if it is not set. Write-only values are only
+// ever available from the configuration.
+func writeOnlyString(d *schema.ResourceData, index string) string {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() || !raw.Type().IsObjectType() || !raw.Type().HasAttribute(index) {
+		return ""
+	}
+	value := raw.GetAttr(index)
+	if value.IsNull() || !value.IsKnown() || !value.Type().Equals(cty.String) {
+		return ""
+	}
+	return value.AsString()
+}
+
+// assignWriteOnlyString sends the write-only attribute index when its
+// version trigger changes. A removed value doesn't clear one already
+// assigned from the stored variant of the attribute.
+func assignWriteOnlyString(d *schema.ResourceData, target **string, index string) {
+	if !d.HasChange(index + "_version") {
+		return
+	}
+	value := writeOnlyString(d, index)
+	if value == "" && *target != nil {
+		return
+	}
+	*target = &value
+}
+
 func assignStringSet(d *schema.ResourceData, target *[]string, index string) {
 	if d.HasChange(index) {
 		*target = sliceFromStringSet(d, index)
@@ -356,6 +439,13 @@ func getenvWithDefault(key string, defaultValue string) string {
 	return defaultValue
 }
 
+// getenvBool reports whether the environment variable named by the key
+// is set to a true value
+func getenvBool(key string) bool {
+	result, _ := strconv.ParseBool(os.Getenv(key))
+	return result
+}
+
 // strSliceContains checks if a given string is contained in a slice
 // When anybody asks why Go needs generics, here you go.
 func strSliceContains(haystack []string, needle string) bool {
@@ -367,29 +457,26 @@ func strSliceContains(haystack []string, needle string) bool {
 	return false
 }
 
-// Check a JSON object is correct
-func validateJSONObject(v interface{}, k string) ([]string, []error) {
-	if v == nil || v.(string) == "" {
-		return nil, []error{fmt.Errorf("%q value must not be empty", k)}
-	}
-
-	var j map[string]interface{}
-	s := v.(string)
-
-	err := json.Unmarshal([]byte(s), &j)
+// Check a timeout is a positive duration, such as "10m"
+func validateTimeout(v interface{}, k string) ([]string, []error) {
+	timeout, err := time.ParseDuration(v.(string))
 	if err != nil {
-		return nil, []error{fmt.Errorf("%q must be a JSON object: %s", k, err)}
+		return nil, []error{fmt.Errorf("%q must be a duration such as \"10m\": %s", k, err)}
+	}
+	if timeout <= 0 {
+		return nil, []error{fmt.Errorf("%q must be a positive duration", k)}
 	}
-
 	return nil, nil
 }
 
-func diffSuppressJSONObject(k, old, new string, d *schema.ResourceData) bool {
-	if strSliceContains([]string{"{}", ""}, old) &&
-		strSliceContains([]string{"{}", ""}, new) {
-		return true
+// durationFromString parses a duration already checked by
+// validateTimeout, falling back to the default timeout
+func durationFromString(s string) time.Duration {
+	timeout, err := time.ParseDuration(s)
+	if err != nil || timeout <= 0 {
+		return defaultTimeout
 	}
-	return false
+	return timeout
 }
 
 // HashcodeString hashes a string to a unique hashcode.
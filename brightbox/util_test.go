@@ -6,7 +6,9 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"golang.org/x/exp/slices"
 	"gotest.tools/v3/assert"
 )
@@ -58,6 +60,20 @@ func TestValidateCron(t *testing.T) {
 	}
 }
 
+func TestValidateTimeout(t *testing.T) {
+	testCases := []StringValidationTestCase{
+		{"Minutes", "10m", false},
+		{"Hours and minutes", "1h30m", false},
+		{"No unit", "10", true},
+		{"Zero", "0s", true},
+		{"Negative", "-5m", true},
+	}
+	es := testStringValidationCases(testCases, validateTimeout)
+	if len(es) > 0 {
+		t.Errorf("Failed to validate timeout: %v", es)
+	}
+}
+
 func TestValidateKeys(t *testing.T) {
 	testCases := []StringMapValidationTestCase{
 		{
@@ -170,3 +186,40 @@ func TestUnion(t *testing.T) {
 func TestDifference(t *testing.T) {
 	assert.DeepEqual(t, []string{"a"}, Difference([]string{"a", "c", "d"}, []string{"b", "c", "d"}))
 }
+
+func TestIDType(t *testing.T) {
+	testCases := map[string]string{
+		"srv-abcde":    "server",
+		"grp-abcde":    "server_group",
+		"vol-abcde":    "volume",
+		"fwr-abcde":    "firewall_rule",
+		"acc-abcde":    "account",
+		"cli-abcde":    "api_client",
+		"cip-abcde":    "cloud_ip",
+		"gb1-a":        "zone",
+		"zon-abcde":    "zone",
+		"srv-abcdef":   "",
+		"cloud-server": "",
+		"":             "",
+	}
+	for id, expected := range testCases {
+		assert.Equal(t, IDType(id), expected, id)
+	}
+}
+
+func TestWriteOnlyString(t *testing.T) {
+	d := resourceBrightboxServer().Data(&terraform.InstanceState{
+		ID: "srv-12345",
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"user_data_wo":         cty.StringVal("#cloud-config"),
+			"user_data_wo_version": cty.NumberIntVal(1),
+			"user_data":            cty.NullVal(cty.String),
+		}),
+	})
+	assert.Equal(t, writeOnlyString(d, "user_data_wo"), "#cloud-config")
+	assert.Equal(t, writeOnlyString(d, "user_data"), "")
+	assert.Equal(t, writeOnlyString(d, "missing"), "")
+
+	d = resourceBrightboxServer().Data(&terraform.InstanceState{ID: "srv-12345"})
+	assert.Equal(t, writeOnlyString(d, "user_data_wo"), "")
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gobrightbox "github.com/brightbox/gobrightbox/v2"
+	"github.com/brightbox/gobrightbox/v2/enums/serverstatus"
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const serverObjectName = "Server"
+
+var (
+	_ action.Action                   = &serverAction{}
+	_ action.ActionWithConfigure      = &serverAction{}
+	_ action.ActionWithValidateConfig = &serverAction{}
+)
+
+// serverAction runs a command against an existing server, then waits
+// for the server to settle in the status the command leaves it in
+type serverAction struct {
+	name        string
+	description string
+	// command runs the operation, returning a progress message
+	// describing what it started
+	command func(context.Context, *gobrightbox.Client, string) (string, error)
+	pending []serverstatus.Enum
+	target  serverstatus.Enum
+
+	client *brightbox.CompositeClient
+}
+
+type serverActionModel struct {
+	Server   types.String   `tfsdk:"server"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// NewServerRebootAction returns the brightbox_server_reboot action
+func NewServerRebootAction() action.Action {
+	return &serverAction{
+		name:        "reboot",
+		description: "Sends a soft reboot to a Brightbox Server, which the operating system may ignore",
+		command: func(ctx context.Context, client *gobrightbox.Client, id string) (string, error) {
+			_, err := client.RebootServer(ctx, id)
+			return "Rebooting " + id, err
+		},
+		pending: []serverstatus.Enum{serverstatus.Inactive},
+		target:  serverstatus.Active,
+	}
+}
+
+// NewServerResetAction returns the brightbox_server_reset action
+func NewServerResetAction() action.Action {
+	return &serverAction{
+		name:        "reset",
+		description: "Hard resets a Brightbox Server, which the operating system cannot ignore",
+		command: func(ctx context.Context, client *gobrightbox.Client, id string) (string, error) {
+			_, err := client.ResetServer(ctx, id)
+			return "Resetting " + id, err
+		},
+		pending: []serverstatus.Enum{serverstatus.Inactive},
+		target:  serverstatus.Active,
+	}
+}
+
+// NewServerShutdownAction returns the brightbox_server_shutdown
+// action. A shut down server is inactive rather than active, so that
+// is the status it waits for.
+func NewServerShutdownAction() action.Action {
+	return &serverAction{
+		name:        "shutdown",
+		description: "Asks the operating system of a Brightbox Server to shut down, and waits for the Server to become inactive",
+		command: func(ctx context.Context, client *gobrightbox.Client, id string) (string, error) {
+			_, err := client.ShutdownServer(ctx, id)
+			return "Shutting down " + id, err
+		},
+		pending: []serverstatus.Enum{serverstatus.Active},
+		target:  serverstatus.Inactive,
+	}
+}
+
+// NewServerSnapshotAction returns the brightbox_server_snapshot action
+func NewServerSnapshotAction() action.Action {
+	return &serverAction{
+		name:        "snapshot",
+		description: "Snapshots the disk of a Brightbox Server to a new image",
+		command: func(ctx context.Context, client *gobrightbox.Client, id string) (string, error) {
+			image, err := brightbox.SnapshotServer(ctx, client, id)
+			if image == "" {
+				return "Snapshotting " + id, err
+			}
+			return fmt.Sprintf("Snapshotting %s to %s", id, image), err
+		},
+		pending: []serverstatus.Enum{serverstatus.Inactive},
+		target:  serverstatus.Active,
+	}
+}
+
+func (a *serverAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_" + a.name
+}
+
+func (a *serverAction) Schema(ctx context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: a.description,
+		Attributes: map[string]schema.Attribute{
+			"server": schema.StringAttribute{
+				Description: "The ID of the Server",
+				Required:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (a *serverAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (a *serverAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var server types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("server"), &server)...)
+	if resp.Diagnostics.HasError() || server.IsNull() || server.IsUnknown() {
+		return
+	}
+	if brightbox.IDType(server.ValueString()) != "server" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("server"),
+			"Invalid Server ID",
+			"server must be a valid Server ID",
+		)
+	}
+}
+
+func (a *serverAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data serverActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	invokeTimeout, diags := data.Timeouts.Invoke(ctx, providerDefaultTimeout(a.client, "update"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+	serverID := data.Server.ValueString()
+	ctx = brightbox.NewLogContext(ctx, serverObjectName, serverID, "invoke")
+
+	client := configuredAPIClient(a.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	tflog.Info(ctx, "Invoking "+serverObjectName+" "+a.name)
+	message, err := a.command(ctx, client, serverID)
+	if err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: message})
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Waiting for %s to become %s", serverID, a.target),
+	})
+	if _, err := brightbox.WaitForServerStatus(ctx, client, serverID, a.pending, a.target, invokeTimeout); err != nil {
+		addError(&resp.Diagnostics, err)
+	}
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gotest.tools/v3/assert"
+)
+
+// serverActionConfig returns the configuration of a server action
+// naming server, with no timeouts block
+func serverActionConfig(t *testing.T, a action.Action, server string) tfsdk.Config {
+	ctx := context.Background()
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	assert.Assert(t, !schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+			"server":   tftypes.NewValue(tftypes.String, server),
+			"timeouts": tftypes.NewValue(configType.AttributeTypes["timeouts"], nil),
+		}),
+	}
+}
+
+// invokeServerAction configures a with client and invokes it against
+// server, returning the response and the progress messages sent
+func invokeServerAction(t *testing.T, a action.Action, client *brightbox.CompositeClient, server string) (action.InvokeResponse, []string) {
+	ctx := context.Background()
+	var configureResp action.ConfigureResponse
+	a.(action.ActionWithConfigure).Configure(ctx, action.ConfigureRequest{ProviderData: client}, &configureResp)
+	assert.Assert(t, !configureResp.Diagnostics.HasError(), configureResp.Diagnostics)
+
+	var messages []string
+	resp := action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) {
+			messages = append(messages, event.Message)
+		},
+	}
+	a.Invoke(ctx, action.InvokeRequest{Config: serverActionConfig(t, a, server)}, &resp)
+	return resp, messages
+}
+
+func TestServerActionValidateConfig(t *testing.T) {
+	testCases := map[string]bool{
+		"srv-12345": true,
+		"vol-12345": false,
+		"srv":       false,
+	}
+	for server, valid := range testCases {
+		a := NewServerRebootAction()
+		var resp action.ValidateConfigResponse
+		a.(action.ActionWithValidateConfig).ValidateConfig(context.Background(),
+			action.ValidateConfigRequest{Config: serverActionConfig(t, a, server)}, &resp)
+		assert.Equal(t, !resp.Diagnostics.HasError(), valid, server)
+	}
+}
+
+func TestServerActionMetadata(t *testing.T) {
+	testCases := map[string]func() action.Action{
+		"brightbox_server_reboot":   NewServerRebootAction,
+		"brightbox_server_reset":    NewServerResetAction,
+		"brightbox_server_shutdown": NewServerShutdownAction,
+		"brightbox_server_snapshot": NewServerSnapshotAction,
+	}
+	for expected, newAction := range testCases {
+		var resp action.MetadataResponse
+		newAction().Metadata(context.Background(), action.MetadataRequest{ProviderTypeName: "brightbox"}, &resp)
+		assert.Equal(t, resp.TypeName, expected)
+	}
+}
+
+func TestServerActionInvokeError(t *testing.T) {
+	var requested []string
+	client := fakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error_name":"invalid_state","errors":["Server is not active"]}`))
+	})
+	testCases := map[string]func() action.Action{
+		"/1.0/servers/srv-12345/reboot":   NewServerRebootAction,
+		"/1.0/servers/srv-12345/reset":    NewServerResetAction,
+		"/1.0/servers/srv-12345/shutdown": NewServerShutdownAction,
+		"/1.0/servers/srv-12345/snapshot": NewServerSnapshotAction,
+	}
+	for expected, newAction := range testCases {
+		requested = nil
+		resp, messages := invokeServerAction(t, newAction(), client, "srv-12345")
+		assert.Assert(t, resp.Diagnostics.HasError(), expected)
+		errors := resp.Diagnostics.Errors()
+		assert.Assert(t, strings.Contains(errors[0].Summary()+errors[0].Detail(), "Server is not active"), errors)
+		assert.DeepEqual(t, requested, []string{"POST " + expected})
+		assert.Equal(t, len(messages), 0)
+	}
+}
+
+func TestServerActionInvokeUnconfigured(t *testing.T) {
+	a := NewServerRebootAction()
+	var resp action.InvokeResponse
+	a.Invoke(context.Background(), action.InvokeRequest{Config: serverActionConfig(t, a, "srv-12345")}, &resp)
+	assert.Assert(t, resp.Diagnostics.HasError())
+	assert.Equal(t, resp.Diagnostics.Errors()[0].Summary(), "Unconfigured provider")
+}
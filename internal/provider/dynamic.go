@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// errUnknownValue is returned when a value is not yet known and so
+// cannot be sent to the API
+var errUnknownValue = errors.New("value is not known")
+
+// jsonFromDynamic converts a dynamic attribute holding an object or
+// map into the plain maps, slices and scalars sent to the API
+func jsonFromDynamic(ctx context.Context, value types.Dynamic) (map[string]interface{}, error) {
+	if value.IsNull() || value.IsUnderlyingValueNull() {
+		return map[string]interface{}{}, nil
+	}
+	if value.IsUnknown() || value.IsUnderlyingValueUnknown() {
+		return nil, errUnknownValue
+	}
+	tfValue, err := value.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := jsonFromTerraform(tfValue)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object or map, got %s", tfValue.Type())
+	}
+	return object, nil
+}
+
+func jsonFromTerraform(value tftypes.Value) (interface{}, error) {
+	if !value.IsKnown() {
+		return nil, errUnknownValue
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+	switch valueType := value.Type(); {
+	case valueType.Is(tftypes.String):
+		var result string
+		err := value.As(&result)
+		return result, err
+	case valueType.Is(tftypes.Bool):
+		var result bool
+		err := value.As(&result)
+		return result, err
+	case valueType.Is(tftypes.Number):
+		result := new(big.Float)
+		if err := value.As(&result); err != nil {
+			return nil, err
+		}
+		if result.IsInt() {
+			if integer, accuracy := result.Int64(); accuracy == big.Exact {
+				return integer, nil
+			}
+		}
+		float, _ := result.Float64()
+		return float, nil
+	case valueType.Is(tftypes.Object{}), valueType.Is(tftypes.Map{}):
+		var elements map[string]tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{}, len(elements))
+		for key, element := range elements {
+			converted, err := jsonFromTerraform(element)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = converted
+		}
+		return result, nil
+	case valueType.Is(tftypes.List{}), valueType.Is(tftypes.Set{}), valueType.Is(tftypes.Tuple{}):
+		var elements []tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, len(elements))
+		for i, element := range elements {
+			converted, err := jsonFromTerraform(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", value.Type())
+}
+
+// dynamicFromJSON converts data returned by the API into a dynamic
+// attribute holding an object
+func dynamicFromJSON(data map[string]interface{}) (types.Dynamic, error) {
+	value, err := attrFromJSON(data)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return types.DynamicValue(value), nil
+}
+
+func attrFromJSON(data interface{}) (attr.Value, error) {
+	switch value := data.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case string:
+		return types.StringValue(value), nil
+	case bool:
+		return types.BoolValue(value), nil
+	case float64:
+		return types.NumberValue(big.NewFloat(value)), nil
+	case int64:
+		return types.NumberValue(new(big.Float).SetInt64(value)), nil
+	case json.Number:
+		number, _, err := big.ParseFloat(value.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		return types.NumberValue(number), nil
+	case map[string]interface{}:
+		attributeTypes := make(map[string]attr.Type, len(value))
+		attributes := make(map[string]attr.Value, len(value))
+		for key, element := range value {
+			converted, err := attrFromJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[key] = converted.Type(context.Background())
+			attributes[key] = converted
+		}
+		result, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("%s: %s", diags[0].Summary(), diags[0].Detail())
+		}
+		return result, nil
+	case []interface{}:
+		elementTypes := make([]attr.Type, len(value))
+		elements := make([]attr.Value, len(value))
+		for i, element := range value {
+			converted, err := attrFromJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes[i] = converted.Type(context.Background())
+			elements[i] = converted
+		}
+		result, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("%s: %s", diags[0].Summary(), diags[0].Detail())
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported value %T", data)
+}
+
+// equivalentJSON reports whether two values encode to the same JSON,
+// so a number written as 1024 in configuration matches 1024.0 from
+// the API
+func equivalentJSON(a interface{}, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var decodedA, decodedB interface{}
+	if json.Unmarshal(encodedA, &decodedA) != nil || json.Unmarshal(encodedB, &decodedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+// dataFromAPI returns the API data as a dynamic value. The prior value
+// is kept when it holds the same data, so that whether it was written
+// as an object or a map in configuration is preserved.
+func dataFromAPI(ctx context.Context, prior types.Dynamic, data map[string]interface{}) (types.Dynamic, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	if !prior.IsNull() && !prior.IsUnknown() {
+		if priorData, err := jsonFromDynamic(ctx, prior); err == nil && equivalentJSON(priorData, data) {
+			return prior, nil
+		}
+	}
+	return dynamicFromJSON(data)
+}
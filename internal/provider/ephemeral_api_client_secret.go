@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const apiClientObjectName = "API Client"
+
+var (
+	_ ephemeral.EphemeralResource                   = &apiClientSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &apiClientSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &apiClientSecretEphemeralResource{}
+)
+
+// NewAPIClientSecretEphemeralResource returns the
+// brightbox_api_client_secret ephemeral resource
+func NewAPIClientSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &apiClientSecretEphemeralResource{}
+}
+
+type apiClientSecretEphemeralResource struct {
+	client *brightbox.CompositeClient
+}
+
+type apiClientSecretModel struct {
+	ID     types.String `tfsdk:"id"`
+	Secret types.String `tfsdk:"secret"`
+}
+
+func (r *apiClientSecretEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_client_secret"
+}
+
+func (r *apiClientSecretEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Rotates the secret of a Brightbox API Client and returns it without storing it in state",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the API Client",
+				Required:    true,
+			},
+			"secret": schema.StringAttribute{
+				Description: "The newly generated secret",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (r *apiClientSecretEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *apiClientSecretEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var id types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() || id.IsNull() || id.IsUnknown() {
+		return
+	}
+	if brightbox.IDType(id.ValueString()) != "api_client" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid API Client ID",
+			"id must be a valid API Client ID",
+		)
+	}
+}
+
+func (r *apiClientSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data apiClientSecretModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = brightbox.NewLogContext(ctx, apiClientObjectName, data.ID.ValueString(), "open")
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	tflog.Info(ctx, "Resetting "+apiClientObjectName+" secret")
+	apiClient, err := client.ResetAPIClientPassword(ctx, data.ID.ValueString())
+	if err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	data.Secret = types.StringValue(apiClient.Secret)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
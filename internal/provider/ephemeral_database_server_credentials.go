@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const databaseServerObjectName = "Database Server"
+
+var (
+	_ ephemeral.EphemeralResource                   = &databaseServerCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &databaseServerCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &databaseServerCredentialsEphemeralResource{}
+)
+
+// NewDatabaseServerCredentialsEphemeralResource returns the
+// brightbox_database_server_credentials ephemeral resource
+func NewDatabaseServerCredentialsEphemeralResource() ephemeral.EphemeralResource {
+	return &databaseServerCredentialsEphemeralResource{}
+}
+
+type databaseServerCredentialsEphemeralResource struct {
+	client *brightbox.CompositeClient
+}
+
+type databaseServerCredentialsModel struct {
+	ID            types.String `tfsdk:"id"`
+	AdminUsername types.String `tfsdk:"admin_username"`
+	AdminPassword types.String `tfsdk:"admin_password"`
+}
+
+func (r *databaseServerCredentialsEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_server_credentials"
+}
+
+func (r *databaseServerCredentialsEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Resets the admin password of a Brightbox Database Server and returns it without storing it in state",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the Database Server",
+				Required:    true,
+			},
+			"admin_username": schema.StringAttribute{
+				Description: "Initial admin user name",
+				Computed:    true,
+			},
+			"admin_password": schema.StringAttribute{
+				Description: "The newly reset admin password",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (r *databaseServerCredentialsEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *databaseServerCredentialsEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var id types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() || id.IsNull() || id.IsUnknown() {
+		return
+	}
+	if brightbox.IDType(id.ValueString()) != "database_server" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Database Server ID",
+			"id must be a valid Database Server ID",
+		)
+	}
+}
+
+func (r *databaseServerCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data databaseServerCredentialsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = brightbox.NewLogContext(ctx, databaseServerObjectName, data.ID.ValueString(), "open")
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	tflog.Info(ctx, "Resetting "+databaseServerObjectName+" password")
+	databaseServer, err := client.ResetDatabaseServerPassword(ctx, data.ID.ValueString())
+	if err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	data.AdminUsername = types.StringValue(databaseServer.AdminUsername)
+	data.AdminPassword = types.StringValue(databaseServer.AdminPassword)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
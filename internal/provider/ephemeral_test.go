@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+)
+
+// fakeAPIProvider returns an SDK provider configured against a server
+// that issues tokens, serves the account and passes every other request
+// to api
+func fakeAPIProvider(t *testing.T, api http.HandlerFunc) *schema.Provider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token/" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "access-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		if r.URL.Path == "/1.0/accounts/acc-12345" {
+			w.Write([]byte(`{"id":"acc-12345","name":"Example","status":"active"}`))
+			return
+		}
+		api(w, r)
+	}))
+	t.Cleanup(server.Close)
+	sdk := brightbox.Provider("test")
+	diags := sdk.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"apiclient": "cli-12345",
+		"apisecret": "mysecret",
+		"account":   "acc-12345",
+		"apiurl":    server.URL,
+	}))
+	assert.Assert(t, !diags.HasError(), diags)
+	return sdk
+}
+
+// fakeAPIClient returns the client of a provider from fakeAPIProvider
+func fakeAPIClient(t *testing.T, api http.HandlerFunc) *brightbox.CompositeClient {
+	return fakeAPIProvider(t, api).Meta().(*brightbox.CompositeClient)
+}
+
+// openEphemeral configures r with client and opens it with the
+// configuration in model, returning the result
+func openEphemeral(t *testing.T, r ephemeral.EphemeralResource, client *brightbox.CompositeClient, model interface{}) ephemeral.OpenResponse {
+	ctx := context.Background()
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	var configureResp ephemeral.ConfigureResponse
+	r.(ephemeral.EphemeralResourceWithConfigure).Configure(ctx, ephemeral.ConfigureRequest{ProviderData: client}, &configureResp)
+	assert.Assert(t, !configureResp.Diagnostics.HasError(), configureResp.Diagnostics)
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, model)
+	assert.Assert(t, !diags.HasError(), diags)
+	resp := ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{
+			Schema: schemaResp.Schema,
+			Raw:    state.Raw.Copy(),
+		},
+	}
+	r.Open(ctx, ephemeral.OpenRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+	return resp
+}
+
+// validateEphemeral validates the configuration in model against r
+func validateEphemeral(t *testing.T, r ephemeral.EphemeralResource, model interface{}) ephemeral.ValidateConfigResponse {
+	ctx := context.Background()
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, model)
+	assert.Assert(t, !diags.HasError(), diags)
+	var resp ephemeral.ValidateConfigResponse
+	r.(ephemeral.EphemeralResourceWithValidateConfig).ValidateConfig(ctx,
+		ephemeral.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+	return resp
+}
+
+func TestDatabaseServerCredentialsOpen(t *testing.T) {
+	client := fakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/1.0/database_servers/dbs-12345/reset_password" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"dbs-12345","admin_username":"admin","admin_password":"fresh-password"}`))
+	})
+	resp := openEphemeral(t, NewDatabaseServerCredentialsEphemeralResource(), client, databaseServerCredentialsModel{
+		ID: types.StringValue("dbs-12345"),
+	})
+	assert.Assert(t, !resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var result databaseServerCredentialsModel
+	diags := resp.Result.Get(context.Background(), &result)
+	assert.Assert(t, !diags.HasError(), diags)
+	assert.Equal(t, result.ID.ValueString(), "dbs-12345")
+	assert.Equal(t, result.AdminUsername.ValueString(), "admin")
+	assert.Equal(t, result.AdminPassword.ValueString(), "fresh-password")
+}
+
+func TestDatabaseServerCredentialsOpenError(t *testing.T) {
+	client := fakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_name":"missing_resource","errors":["Resource not found"]}`))
+	})
+	resp := openEphemeral(t, NewDatabaseServerCredentialsEphemeralResource(), client, databaseServerCredentialsModel{
+		ID: types.StringValue("dbs-12345"),
+	})
+	assert.Assert(t, resp.Diagnostics.HasError())
+}
+
+func TestDatabaseServerCredentialsValidateConfig(t *testing.T) {
+	testCases := map[string]bool{
+		"dbs-12345": true,
+		"srv-12345": false,
+		"dbs":       false,
+	}
+	for id, valid := range testCases {
+		resp := validateEphemeral(t, NewDatabaseServerCredentialsEphemeralResource(), databaseServerCredentialsModel{
+			ID: types.StringValue(id),
+		})
+		assert.Equal(t, !resp.Diagnostics.HasError(), valid, id)
+	}
+}
+
+func TestAPIClientSecretOpen(t *testing.T) {
+	client := fakeAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/1.0/api_clients/cli-abcde/reset_secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"cli-abcde","secret":"fresh-secret"}`))
+	})
+	resp := openEphemeral(t, NewAPIClientSecretEphemeralResource(), client, apiClientSecretModel{
+		ID: types.StringValue("cli-abcde"),
+	})
+	assert.Assert(t, !resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var result apiClientSecretModel
+	diags := resp.Result.Get(context.Background(), &result)
+	assert.Assert(t, !diags.HasError(), diags)
+	assert.Equal(t, result.Secret.ValueString(), "fresh-secret")
+}
+
+func TestAPIClientSecretValidateConfig(t *testing.T) {
+	testCases := map[string]bool{
+		"cli-abcde": true,
+		"dbs-12345": false,
+		"cli":       false,
+	}
+	for id, valid := range testCases {
+		resp := validateEphemeral(t, NewAPIClientSecretEphemeralResource(), apiClientSecretModel{
+			ID: types.StringValue(id),
+		})
+		assert.Equal(t, !resp.Diagnostics.HasError(), valid, id)
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &idTypeFunction{}
+
+// NewIDTypeFunction returns the id_type function
+func NewIDTypeFunction() function.Function {
+	return &idTypeFunction{}
+}
+
+type idTypeFunction struct{}
+
+func (f *idTypeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "id_type"
+}
+
+func (f *idTypeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Type of object a Brightbox identifier refers to",
+		Description: "Returns the type of object a Brightbox identifier or zone handle refers to, " +
+			"such as \"server\" for \"srv-abcde\", or an empty string if it is not recognised.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "id",
+				Description: "Brightbox identifier or zone handle",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *idTypeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, brightbox.IDType(id)))
+}
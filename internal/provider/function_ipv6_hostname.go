@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ipv6HostnameFunction{}
+
+// NewIPv6HostnameFunction returns the ipv6_hostname function
+func NewIPv6HostnameFunction() function.Function {
+	return &ipv6HostnameFunction{}
+}
+
+type ipv6HostnameFunction struct{}
+
+func (f *ipv6HostnameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ipv6_hostname"
+}
+
+func (f *ipv6HostnameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "IPv6 hostname of a server",
+		Description: "Returns the hostname that resolves to the IPv6 address of the server with the given FQDN, " +
+			"as found in the ipv6_hostname attribute of brightbox_server.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "fqdn",
+				Description: "Fully qualified domain name of the server",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ipv6HostnameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &fqdn))
+	if resp.Error != nil {
+		return
+	}
+	if !brightbox.IsDNSName(fqdn) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q is not a valid fully qualified domain name", fqdn))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, brightbox.IPv6Hostname(fqdn)))
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &userDataHashFunction{}
+
+// NewUserDataHashFunction returns the user_data_hash function
+func NewUserDataHashFunction() function.Function {
+	return &userDataHashFunction{}
+}
+
+type userDataHashFunction struct{}
+
+func (f *userDataHashFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "user_data_hash"
+}
+
+func (f *userDataHashFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Hash of server user data",
+		Description: "Returns the hash stored in the user_data attribute of brightbox_server " +
+			"in place of the user data itself, so the two can be compared.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "user_data",
+				Description: "User data, as given to brightbox_server",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *userDataHashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var userData string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &userData))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, brightbox.UserDataHash(userData)))
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gotest.tools/v3/assert"
+)
+
+// runStringFunction calls a function taking and returning a string
+func runStringFunction(t *testing.T, f function.Function, argument string) (string, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	resp := function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+	f.Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(argument)}),
+	}, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	result, ok := resp.Result.Value().(types.String)
+	assert.Assert(t, ok)
+	return result.ValueString(), nil
+}
+
+func TestIDTypeFunction(t *testing.T) {
+	testCases := map[string]string{
+		"srv-abcde": "server",
+		"lba-abcde": "load_balancer",
+		"cip-abcde": "cloud_ip",
+		"cli-abcde": "api_client",
+		"gb1s-b":    "zone",
+		"web":       "",
+	}
+	for id, expected := range testCases {
+		result, err := runStringFunction(t, NewIDTypeFunction(), id)
+		assert.Assert(t, err == nil, err)
+		assert.Equal(t, result, expected, id)
+	}
+}
+
+func TestUserDataHashFunction(t *testing.T) {
+	result, err := runStringFunction(t, NewUserDataHashFunction(), "#cloud-config\n")
+	assert.Assert(t, err == nil, err)
+	assert.Equal(t, result, "c0af657a8122db11fb8541f90feab7871c2c3d71")
+}
+
+func TestIPv6HostnameFunction(t *testing.T) {
+	result, err := runStringFunction(t, NewIPv6HostnameFunction(), "srv-abcde.gb1.brightbox.com")
+	assert.Assert(t, err == nil, err)
+	assert.Equal(t, result, "ipv6.srv-abcde.gb1.brightbox.com")
+
+	for _, invalid := range []string{"", "-srv.example.com"} {
+		_, err = runStringFunction(t, NewIPv6HostnameFunction(), invalid)
+		assert.Assert(t, err != nil, invalid)
+	}
+}
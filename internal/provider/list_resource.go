@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	ctymsgpack "github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sdkdiag "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	_ list.ListResource                 = &sdkListResource{}
+	_ list.ListResourceWithConfigure    = &sdkListResource{}
+	_ list.ListResourceWithRawV5Schemas = &sdkListResource{}
+)
+
+// listFilterDescriptions describes the filters a list block may set
+var listFilterDescriptions = map[string]string{
+	"name":   "Regular expression the object name must match",
+	"zone":   "Zone ID or handle the object must be in",
+	"status": "Status the object must have",
+}
+
+// sdkListResource lists the objects of a managed resource served by
+// the SDKv2 provider, so terraform query can find objects to import
+type sdkListResource struct {
+	sdk      *schema.Provider
+	typeName string
+	filters  []string
+	client   *brightbox.CompositeClient
+}
+
+// newSDKListResource returns a list resource for the SDKv2 managed
+// resource typeName, filtered by the named list block attributes
+func newSDKListResource(sdk *schema.Provider, typeName string, filters ...string) func() list.ListResource {
+	return func() list.ListResource {
+		return &sdkListResource{
+			sdk:      sdk,
+			typeName: typeName,
+			filters:  filters,
+		}
+	}
+}
+
+func (r *sdkListResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = r.typeName
+}
+
+// ListResourceConfigSchema also reports any problem reading the SDKv2
+// schemas, as RawV5Schemas has no way to return diagnostics
+func (r *sdkListResource) ListResourceConfigSchema(ctx context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	_, _, diags := r.sdkSchemas(ctx)
+	resp.Diagnostics.Append(diags...)
+	attributes := make(map[string]listschema.Attribute, len(r.filters))
+	for _, filter := range r.filters {
+		attributes[filter] = listschema.StringAttribute{
+			Description: listFilterDescriptions[filter],
+			Optional:    true,
+		}
+	}
+	resp.Schema = listschema.Schema{
+		Description: fmt.Sprintf("Lists %s objects to import", r.typeName),
+		Attributes:  attributes,
+	}
+}
+
+// RawV5Schemas supplies the schemas of the SDKv2 resource, which the
+// framework needs to build list results
+func (r *sdkListResource) RawV5Schemas(ctx context.Context, _ list.RawV5SchemaRequest, resp *list.RawV5SchemaResponse) {
+	var diags diag.Diagnostics
+	resp.ProtoV5Schema, resp.ProtoV5IdentitySchema, diags = r.sdkSchemas(ctx)
+	for _, d := range diags.Errors() {
+		tflog.Error(ctx, d.Summary(), map[string]interface{}{
+			"detail": d.Detail(),
+		})
+	}
+}
+
+// sdkSchemas returns the schema and identity schema of the SDKv2
+// resource being listed
+func (r *sdkListResource) sdkSchemas(ctx context.Context) (*tfprotov5.Schema, *tfprotov5.ResourceIdentitySchema, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	server := r.sdk.GRPCProvider()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		diags.AddError("Unable to read provider schema", err.Error())
+		return nil, nil, diags
+	}
+	appendProtoDiagnostics(&diags, schemas.Diagnostics)
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		diags.AddError("Unable to read resource identity schemas", err.Error())
+		return nil, nil, diags
+	}
+	appendProtoDiagnostics(&diags, identities.Diagnostics)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	resourceSchema := schemas.ResourceSchemas[r.typeName]
+	identitySchema := identities.IdentitySchemas[r.typeName]
+	if resourceSchema == nil || identitySchema == nil {
+		diags.AddError(
+			"Missing resource schema",
+			fmt.Sprintf("No schema and identity schema were found for %s. This is a bug in the provider, please report it.", r.typeName),
+		)
+		return nil, nil, diags
+	}
+	return resourceSchema, identitySchema, diags
+}
+
+// appendProtoDiagnostics adds the diagnostics returned by a protocol
+// call to diags
+func appendProtoDiagnostics(diags *diag.Diagnostics, protoDiags []*tfprotov5.Diagnostic) {
+	for _, d := range protoDiags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			diags.AddError(d.Summary, d.Detail)
+		} else {
+			diags.AddWarning(d.Summary, d.Detail)
+		}
+	}
+}
+
+func (r *sdkListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *sdkListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var diags diag.Diagnostics
+	var filter brightbox.ListFilter
+	targets := map[string]*string{
+		"name":   &filter.Name,
+		"zone":   &filter.Zone,
+		"status": &filter.Status,
+	}
+	for _, name := range r.filters {
+		var value types.String
+		diags.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		*targets[name] = value.ValueString()
+	}
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	if r.client == nil {
+		addUnconfiguredError(&diags)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	objects, err := brightbox.ListObjects(ctx, r.client, r.typeName, filter)
+	if err != nil {
+		addError(&diags, err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	if req.Limit > 0 && int64(len(objects)) > req.Limit {
+		objects = objects[:req.Limit]
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, object := range objects {
+			if !push(r.listResult(ctx, req, object)) {
+				return
+			}
+		}
+	}
+}
+
+func (r *sdkListResource) listResult(ctx context.Context, req list.ListRequest, object brightbox.ListedObject) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = object.ID
+	if object.Name != "" {
+		result.DisplayName = fmt.Sprintf("%s (%s)", object.Name, object.ID)
+	}
+	result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root("id"), object.ID)...)
+	if !req.IncludeResource || result.Diagnostics.HasError() {
+		return result
+	}
+
+	state, sdkDiags := object.State()
+	for _, d := range sdkDiags {
+		if d.Severity == sdkdiag.Error {
+			result.Diagnostics.AddError(d.Summary, d.Detail)
+		} else {
+			result.Diagnostics.AddWarning(d.Summary, d.Detail)
+		}
+	}
+	if result.Diagnostics.HasError() {
+		return result
+	}
+	packed, err := ctymsgpack.Marshal(state, state.Type())
+	if err != nil {
+		result.Diagnostics.AddError("Unable to encode "+r.typeName+" state", err.Error())
+		return result
+	}
+	value, err := (&tfprotov5.DynamicValue{MsgPack: packed}).Unmarshal(req.ResourceSchema.Type().TerraformType(ctx))
+	if err != nil {
+		result.Diagnostics.AddError("Unable to decode "+r.typeName+" state", err.Error())
+		return result
+	}
+	result.Resource.Raw = value
+	return result
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gotest.tools/v3/assert"
+)
+
+const testVolumeList = `[
+	{"id":"vol-aaaaa","name":"data","status":"attached","size":40960,"storage_type":"network"},
+	{"id":"vol-bbbbb","name":"scratch","status":"detached","size":40960,"storage_type":"network"},
+	{"id":"vol-ccccc","name":"old","status":"deleted","size":40960,"storage_type":"network"}
+]`
+
+// listVolumes runs the brightbox_volume list resource with the given
+// filter values through the protocol server
+func listVolumes(t *testing.T, filters map[string]tftypes.Value, includeResource bool, limit int64) []tfprotov5.ListResourceResult {
+	ctx := context.Background()
+	sdk := fakeAPIProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.0/volumes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(testVolumeList))
+	})
+	server := providerserver.NewProtocol5(New("test", sdk)())()
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	assert.NilError(t, err)
+	providerConfig, err := tfprotov5.NewDynamicValue(schemas.Provider.ValueType(), tftypes.NewValue(schemas.Provider.ValueType(), nil))
+	assert.NilError(t, err)
+	configured, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &providerConfig})
+	assert.NilError(t, err)
+	assert.Equal(t, len(configured.Diagnostics), 0)
+
+	configType := schemas.ListResourceSchemas["brightbox_volume"].ValueType()
+	values := map[string]tftypes.Value{
+		"name":   tftypes.NewValue(tftypes.String, nil),
+		"status": tftypes.NewValue(tftypes.String, nil),
+	}
+	for name, value := range filters {
+		values[name] = value
+	}
+	config, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	assert.NilError(t, err)
+	stream, err := server.(tfprotov5.ProviderServerWithListResource).ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        "brightbox_volume",
+		Config:          &config,
+		IncludeResource: includeResource,
+		Limit:           limit,
+	})
+	assert.NilError(t, err)
+
+	var results []tfprotov5.ListResourceResult
+	for result := range stream.Results {
+		for _, diag := range result.Diagnostics {
+			assert.Assert(t, diag.Severity != tfprotov5.DiagnosticSeverityError, "%s: %s", diag.Summary, diag.Detail)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// identityID returns the id recorded in the identity of a list result
+func identityID(t *testing.T, result tfprotov5.ListResourceResult) string {
+	identityType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}}
+	value, err := result.Identity.IdentityData.Unmarshal(identityType)
+	assert.NilError(t, err)
+	var attributes map[string]tftypes.Value
+	assert.NilError(t, value.As(&attributes))
+	var id string
+	assert.NilError(t, attributes["id"].As(&id))
+	return id
+}
+
+func TestListResourceVolumes(t *testing.T) {
+	results := listVolumes(t, nil, false, 100)
+	assert.Equal(t, len(results), 2)
+	assert.Equal(t, results[0].DisplayName, "data (vol-aaaaa)")
+	assert.Equal(t, identityID(t, results[0]), "vol-aaaaa")
+	assert.Equal(t, identityID(t, results[1]), "vol-bbbbb")
+	assert.Assert(t, results[0].Resource == nil)
+}
+
+func TestListResourceVolumesFiltered(t *testing.T) {
+	results := listVolumes(t, map[string]tftypes.Value{
+		"status": tftypes.NewValue(tftypes.String, "detached"),
+	}, false, 100)
+	assert.Equal(t, len(results), 1)
+	assert.Equal(t, identityID(t, results[0]), "vol-bbbbb")
+
+	results = listVolumes(t, nil, false, 1)
+	assert.Equal(t, len(results), 1)
+}
+
+func TestListResourceVolumesIncludeResource(t *testing.T) {
+	results := listVolumes(t, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "^data$"),
+	}, true, 100)
+	assert.Equal(t, len(results), 1)
+	assert.Assert(t, results[0].Resource != nil)
+}
+
+func TestListResourceSchemaErrors(t *testing.T) {
+	ctx := context.Background()
+	sdk := brightbox.Provider("test")
+
+	r := newSDKListResource(sdk, "brightbox_volume")().(*sdkListResource)
+	var found list.RawV5SchemaResponse
+	r.RawV5Schemas(ctx, list.RawV5SchemaRequest{}, &found)
+	assert.Assert(t, found.ProtoV5Schema != nil)
+	assert.Assert(t, found.ProtoV5IdentitySchema != nil)
+	var foundSchema list.ListResourceSchemaResponse
+	r.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &foundSchema)
+	assert.Assert(t, !foundSchema.Diagnostics.HasError(), foundSchema.Diagnostics)
+
+	r = newSDKListResource(sdk, "brightbox_missing")().(*sdkListResource)
+	var missing list.RawV5SchemaResponse
+	r.RawV5Schemas(ctx, list.RawV5SchemaRequest{}, &missing)
+	assert.Assert(t, missing.ProtoV5Schema == nil)
+	assert.Assert(t, missing.ProtoV5IdentitySchema == nil)
+
+	var schemaResp list.ListResourceSchemaResponse
+	r.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &schemaResp)
+	assert.Equal(t, schemaResp.Diagnostics.Errors()[0].Summary(), "Missing resource schema")
+}
@@ -0,0 +1,183 @@
+// Package provider serves the Brightbox resources built on the
+// Terraform Plugin Framework. It is muxed with the SDKv2 provider in
+// the brightbox package and shares its configuration and clients.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gobrightbox "github.com/brightbox/gobrightbox/v2"
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	_ provider.Provider                       = &brightboxProvider{}
+	_ provider.ProviderWithFunctions          = &brightboxProvider{}
+	_ provider.ProviderWithEphemeralResources = &brightboxProvider{}
+	_ provider.ProviderWithListResources      = &brightboxProvider{}
+	_ provider.ProviderWithActions            = &brightboxProvider{}
+)
+
+type brightboxProvider struct {
+	version string
+	sdk     *schema.Provider
+}
+
+// New returns the framework provider. sdk is the SDKv2 provider it is
+// muxed with, which must be served, and so configured, first: its
+// client is reused rather than authenticating a second time.
+func New(version string, sdk *schema.Provider) func() provider.Provider {
+	return func() provider.Provider {
+		return &brightboxProvider{
+			version: version,
+			sdk:     sdk,
+		}
+	}
+}
+
+func (p *brightboxProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "brightbox"
+	resp.Version = p.version
+}
+
+// Schema mirrors the SDKv2 provider schema, as the mux requires every
+// server to declare the same provider configuration
+func (p *brightboxProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema, err := p.sdk.GRPCProvider().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read provider schema", err.Error())
+		return
+	}
+	result, err := providerSchema(sdkSchema.Provider)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to convert provider schema", err.Error())
+		return
+	}
+	resp.Schema = result
+}
+
+func (p *brightboxProvider) Configure(_ context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	client, ok := p.sdk.Meta().(*brightbox.CompositeClient)
+	if !ok || client == nil {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The SDKv2 provider must be configured before the framework provider so they can share a client. "+
+				"This is a bug in the provider, please report it.",
+		)
+		return
+	}
+	resp.ResourceData = client
+	resp.DataSourceData = client
+	resp.EphemeralResourceData = client
+	resp.ListResourceData = client
+	resp.ActionData = client
+	if !client.ConfigKnown() && req.ClientCapabilities.DeferralAllowed {
+		resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+	}
+}
+
+func (p *brightboxProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewConfigMapResource,
+	}
+}
+
+func (p *brightboxProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *brightboxProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAPIClientSecretEphemeralResource,
+		NewDatabaseServerCredentialsEphemeralResource,
+	}
+}
+
+// ListResources lets terraform query find existing objects of the
+// SDKv2 resources, which declare the identities it needs
+func (p *brightboxProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		newSDKListResource(p.sdk, "brightbox_cloudip", "name", "status"),
+		newSDKListResource(p.sdk, "brightbox_firewall_policy", "name"),
+		newSDKListResource(p.sdk, "brightbox_load_balancer", "name", "status"),
+		newSDKListResource(p.sdk, "brightbox_server", "name", "zone", "status"),
+		newSDKListResource(p.sdk, "brightbox_volume", "name", "status"),
+	}
+}
+
+func (p *brightboxProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewServerRebootAction,
+		NewServerResetAction,
+		NewServerShutdownAction,
+		NewServerSnapshotAction,
+	}
+}
+
+func (p *brightboxProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewIDTypeFunction,
+		NewIPv6HostnameFunction,
+		NewUserDataHashFunction,
+	}
+}
+
+// clientFromProviderData returns the client passed to Configure by the
+// provider. It is nil, without error, when the provider has not been
+// configured yet.
+func clientFromProviderData(data any, diags *diag.Diagnostics) *brightbox.CompositeClient {
+	if data == nil {
+		return nil
+	}
+	client, ok := data.(*brightbox.CompositeClient)
+	if !ok {
+		diags.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *brightbox.CompositeClient, got %T. This is a bug in the provider, please report it.", data),
+		)
+		return nil
+	}
+	return client
+}
+
+// providerDefaultTimeout returns the provider default for the
+// operation named by key
+func providerDefaultTimeout(client *brightbox.CompositeClient, key string) time.Duration {
+	return client.DefaultTimeout(key)
+}
+
+// configuredAPIClient returns the infrastructure client, adding an
+// error to diags if the provider was not configured or the client
+// cannot be built
+func configuredAPIClient(client *brightbox.CompositeClient, diags *diag.Diagnostics) *gobrightbox.Client {
+	if client == nil {
+		addUnconfiguredError(diags)
+		return nil
+	}
+	result, err := client.APIClient()
+	if err != nil {
+		addError(diags, err)
+		return nil
+	}
+	return result
+}
+
+func addUnconfiguredError(diags *diag.Diagnostics) {
+	diags.AddError(
+		"Unconfigured provider",
+		"No Brightbox client is available because the provider has not been configured. "+
+			"This is a bug in the provider, please report it.",
+	)
+}
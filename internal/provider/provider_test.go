@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+)
+
+// muxServer combines the two providers the way main does
+func muxServer(ctx context.Context, sdk *schema.Provider) (tfprotov5.ProviderServer, error) {
+	server, err := tf5muxserver.NewMuxServer(ctx,
+		sdk.GRPCProvider,
+		providerserver.NewProtocol5(New("test", sdk)()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return server.ProviderServer(), nil
+}
+
+func testAccProtoV5ProviderFactories() map[string]func() (tfprotov5.ProviderServer, error) {
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"brightbox": func() (tfprotov5.ProviderServer, error) {
+			return muxServer(context.Background(), brightbox.Provider("test"))
+		},
+	}
+}
+
+func TestMuxServerSchema(t *testing.T) {
+	server, err := muxServer(context.Background(), brightbox.Provider("test"))
+	assert.NilError(t, err)
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	assert.NilError(t, err)
+	for _, diag := range resp.Diagnostics {
+		assert.Assert(t, diag.Severity != tfprotov5.DiagnosticSeverityError, "%s: %s", diag.Summary, diag.Detail)
+	}
+	assert.Assert(t, resp.ResourceSchemas["brightbox_config_map"] != nil)
+	assert.Assert(t, resp.ResourceSchemas["brightbox_server"] != nil)
+	for _, name := range []string{"brightbox_api_client_secret", "brightbox_database_server_credentials"} {
+		assert.Assert(t, resp.EphemeralResourceSchemas[name] != nil, name)
+	}
+	for _, name := range []string{"brightbox_cloudip", "brightbox_firewall_policy", "brightbox_load_balancer", "brightbox_server", "brightbox_volume"} {
+		assert.Assert(t, resp.ListResourceSchemas[name] != nil, name)
+	}
+	for _, name := range []string{"brightbox_server_reboot", "brightbox_server_reset", "brightbox_server_shutdown", "brightbox_server_snapshot"} {
+		assert.Assert(t, resp.ActionSchemas[name] != nil, name)
+	}
+	for _, name := range []string{"id_type", "ipv6_hostname", "user_data_hash"} {
+		assert.Assert(t, resp.Functions[name] != nil, name)
+	}
+}
+
+func TestMuxServerIdentitySchemas(t *testing.T) {
+	server, err := muxServer(context.Background(), brightbox.Provider("test"))
+	assert.NilError(t, err)
+	schemas, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	assert.NilError(t, err)
+	resp, err := server.GetResourceIdentitySchemas(context.Background(), &tfprotov5.GetResourceIdentitySchemasRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(resp.Diagnostics), 0)
+	for name := range schemas.ResourceSchemas {
+		assert.Assert(t, resp.IdentitySchemas[name] != nil, name)
+	}
+}
+
+func TestConfigureSharesClient(t *testing.T) {
+	sdk := brightbox.Provider("test")
+	frameworkProvider := New("test", sdk)()
+
+	var resp provider.ConfigureResponse
+	frameworkProvider.Configure(context.Background(), provider.ConfigureRequest{}, &resp)
+	assert.Assert(t, resp.Diagnostics.HasError())
+
+	diags := sdk.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"apiclient": "cli-12345",
+		"apisecret": "mysecret",
+	}))
+	assert.Assert(t, !diags.HasError())
+
+	resp = provider.ConfigureResponse{}
+	frameworkProvider.Configure(context.Background(), provider.ConfigureRequest{}, &resp)
+	assert.Assert(t, !resp.Diagnostics.HasError())
+	assert.Equal(t, resp.ResourceData, sdk.Meta())
+	assert.Equal(t, resp.EphemeralResourceData, sdk.Meta())
+	assert.Equal(t, resp.ListResourceData, sdk.Meta())
+	assert.Equal(t, resp.ActionData, sdk.Meta())
+}
+
+// objectValue returns a value of typ with the given attributes set
+// and the rest null
+func objectValue(t *testing.T, typ tftypes.Type, attributes map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	objectType := typ.(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	result, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	assert.NilError(t, err)
+	return &result
+}
+
+// configureUnknown configures server with an API client ID that is
+// not known until apply
+func configureUnknown(t *testing.T, server tfprotov5.ProviderServer, deferralAllowed bool) *tfprotov5.GetProviderSchemaResponse {
+	ctx := context.Background()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	assert.NilError(t, err)
+	resp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.9.0",
+		Config: objectValue(t, schemas.Provider.ValueType(), map[string]tftypes.Value{
+			"apiclient": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+		ClientCapabilities: &tfprotov5.ConfigureProviderClientCapabilities{
+			DeferralAllowed: deferralAllowed,
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(resp.Diagnostics), 0)
+	return schemas
+}
+
+func TestPlanWithUnknownProviderConfig(t *testing.T) {
+	ctx := context.Background()
+	server, err := muxServer(ctx, brightbox.Provider("test"))
+	assert.NilError(t, err)
+	schemas := configureUnknown(t, server, false)
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	assert.NilError(t, err)
+
+	groupType := schemas.ResourceSchemas["brightbox_server_group"].ValueType()
+	config := map[string]tftypes.Value{
+		"name":        tftypes.NewValue(tftypes.String, "web"),
+		"description": tftypes.NewValue(tftypes.String, "web servers"),
+	}
+	state := map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "grp-12345"),
+		"default":         tftypes.NewValue(tftypes.Bool, false),
+		"fqdn":            tftypes.NewValue(tftypes.String, "grp-12345.gb1.brightbox.com"),
+		"firewall_policy": tftypes.NewValue(tftypes.String, "fwp-12345"),
+	}
+	for name, value := range config {
+		state[name] = value
+	}
+	priorState := objectValue(t, groupType, state)
+
+	readResp, err := server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:     "brightbox_server_group",
+		CurrentState: priorState,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(readResp.Diagnostics), 0)
+	assert.Assert(t, readResp.Deferred == nil)
+	identityType := identities.IdentitySchemas["brightbox_server_group"].ValueType()
+	identity, err := readResp.NewIdentity.IdentityData.Unmarshal(identityType)
+	assert.NilError(t, err)
+	assert.Assert(t, identity.Equal(tftypes.NewValue(identityType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "grp-12345"),
+	})), identity)
+	newState, err := readResp.NewState.Unmarshal(groupType)
+	assert.NilError(t, err)
+	expected, err := priorState.Unmarshal(groupType)
+	assert.NilError(t, err)
+	assert.Assert(t, newState.Equal(expected), newState)
+
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "brightbox_server_group",
+		PriorState:       readResp.NewState,
+		ProposedNewState: readResp.NewState,
+		Config:           objectValue(t, groupType, config),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(planResp.Diagnostics), 0)
+	assert.Equal(t, len(planResp.RequiresReplace), 0)
+}
+
+func TestDeferWithUnknownProviderConfig(t *testing.T) {
+	ctx := context.Background()
+	server, err := muxServer(ctx, brightbox.Provider("test"))
+	assert.NilError(t, err)
+	schemas := configureUnknown(t, server, true)
+
+	for _, name := range []string{"brightbox_server_group", "brightbox_config_map"} {
+		resp, err := server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+			TypeName: name,
+			CurrentState: objectValue(t, schemas.ResourceSchemas[name].ValueType(), map[string]tftypes.Value{
+				"id": tftypes.NewValue(tftypes.String, "grp-12345"),
+			}),
+			ClientCapabilities: &tfprotov5.ReadResourceClientCapabilities{DeferralAllowed: true},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(resp.Diagnostics), 0, name)
+		assert.Assert(t, resp.Deferred != nil, name)
+		assert.Equal(t, resp.Deferred.Reason, tfprotov5.DeferredReasonProviderConfigUnknown, name)
+	}
+
+	dataResp, err := server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+		TypeName: "brightbox_server_type",
+		Config: objectValue(t, schemas.DataSourceSchemas["brightbox_server_type"].ValueType(), map[string]tftypes.Value{
+			"handle": tftypes.NewValue(tftypes.String, "1gb.ssd"),
+		}),
+		ClientCapabilities: &tfprotov5.ReadDataSourceClientCapabilities{DeferralAllowed: true},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(dataResp.Diagnostics), 0)
+	assert.Assert(t, dataResp.Deferred != nil)
+	assert.Equal(t, dataResp.Deferred.Reason, tfprotov5.DeferredReasonProviderConfigUnknown)
+}
+
+func TestDataSourceWithUnknownProviderConfig(t *testing.T) {
+	ctx := context.Background()
+	server, err := muxServer(ctx, brightbox.Provider("test"))
+	assert.NilError(t, err)
+	schemas := configureUnknown(t, server, false)
+
+	resp, err := server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+		TypeName: "brightbox_server_type",
+		Config: objectValue(t, schemas.DataSourceSchemas["brightbox_server_type"].ValueType(), map[string]tftypes.Value{
+			"handle": tftypes.NewValue(tftypes.String, "1gb.ssd"),
+		}),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(resp.Diagnostics), 1)
+	assert.Equal(t, resp.Diagnostics[0].Summary, "The provider configuration depends on values that are not known until apply")
+}
+
+// testAccProvider is configured from the environment to check
+// resources from outside Terraform
+var testAccProvider = brightbox.Provider("test")
+
+var testAccProviderConfigure sync.Once
+
+func testAccPreCheck(t *testing.T) {
+	testAccProviderConfigure.Do(
+		func() {
+			if v := os.Getenv("BRIGHTBOX_CLIENT"); v != "" {
+				if v := os.Getenv("BRIGHTBOX_CLIENT_SECRET"); v == "" {
+					t.Fatal("BRIGHTBOX_CLIENT_SECRET must be set for acceptance tests")
+				}
+			} else if v := os.Getenv("BRIGHTBOX_USER_NAME"); v == "" {
+				t.Fatal("BRIGHTBOX_CLIENT or BRIGHTBOX_USER_NAME must be set for acceptance tests")
+			}
+
+			diags := testAccProvider.Configure(context.TODO(), terraform.NewResourceConfigRaw(nil))
+			if diags.HasError() {
+				t.Fatal(diags[0].Summary)
+			}
+		},
+	)
+}
+
+// This delegation activates the sweepers
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
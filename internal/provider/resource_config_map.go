@@ -0,0 +1,315 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	brightboxapi "github.com/brightbox/gobrightbox/v2"
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const configMapObjectName = "Config Map"
+
+var (
+	_ resource.Resource                   = &configMapResource{}
+	_ resource.ResourceWithConfigure      = &configMapResource{}
+	_ resource.ResourceWithImportState    = &configMapResource{}
+	_ resource.ResourceWithIdentity       = &configMapResource{}
+	_ resource.ResourceWithUpgradeState   = &configMapResource{}
+	_ resource.ResourceWithValidateConfig = &configMapResource{}
+)
+
+// NewConfigMapResource returns the brightbox_config_map resource
+func NewConfigMapResource() resource.Resource {
+	return &configMapResource{}
+}
+
+type configMapResource struct {
+	client *brightbox.CompositeClient
+}
+
+type configMapResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	Name     types.String   `tfsdk:"name"`
+	Data     types.Dynamic  `tfsdk:"data"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// configMapTimeoutTypes describes the timeouts block, which is null in
+// upgraded state
+var configMapTimeoutTypes = map[string]attr.Type{
+	"create": types.StringType,
+	"delete": types.StringType,
+}
+
+func (r *configMapResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_map"
+}
+
+func (r *configMapResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Provides a Brightbox Config Map resource",
+		Version:     1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the Config Map",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "User editable label",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"data": schema.DynamicAttribute{
+				Description: "keys/values making up the ConfigMap. Values may be strings, numbers, booleans, lists or objects",
+				Required:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *configMapResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *configMapResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data types.Dynamic
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("data"), &data)...)
+	if resp.Diagnostics.HasError() || data.IsNull() || data.IsUnknown() || data.IsUnderlyingValueUnknown() {
+		return
+	}
+	switch data.UnderlyingValue().(type) {
+	case basetypes.ObjectValue, basetypes.MapValue:
+		return
+	}
+	resp.Diagnostics.AddAttributeError(
+		path.Root("data"),
+		"Invalid Config Map data",
+		"data must be an object or map of keys to values",
+	)
+}
+
+func (r *configMapResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan configMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, providerDefaultTimeout(r.client, "create"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+	ctx = brightbox.NewLogContext(ctx, configMapObjectName, "", "create")
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	opts, err := configMapOptions(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("data"), "Invalid Config Map data", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Creating "+configMapObjectName)
+	configMap, err := client.CreateConfigMap(ctx, opts)
+	if err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	resp.Diagnostics.Append(setConfigMapModel(ctx, &plan, configMap)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setIDIdentity(ctx, resp.Identity, plan.ID)...)
+}
+
+func (r *configMapResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state configMapResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = brightbox.NewLogContext(ctx, configMapObjectName, state.ID.ValueString(), "read")
+	if r.client != nil && !r.client.ConfigKnown() {
+		tflog.Warn(ctx, "Provider configuration is not yet known, keeping the prior state")
+		return
+	}
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	configMap, err := client.ConfigMap(ctx, state.ID.ValueString())
+	if err != nil {
+		var apierror *brightboxapi.APIError
+		if errors.As(err, &apierror) && apierror.StatusCode == 404 {
+			tflog.Warn(ctx, configMapObjectName+" not found, removing from state")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	resp.Diagnostics.Append(setConfigMapModel(ctx, &state, configMap)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setIDIdentity(ctx, resp.Identity, state.ID)...)
+}
+
+func (r *configMapResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan configMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = brightbox.NewLogContext(ctx, configMapObjectName, plan.ID.ValueString(), "update")
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	opts, err := configMapOptions(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("data"), "Invalid Config Map data", err.Error())
+		return
+	}
+	opts.ID = plan.ID.ValueString()
+	tflog.Info(ctx, "Updating "+configMapObjectName)
+	configMap, err := client.UpdateConfigMap(ctx, opts)
+	if err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	resp.Diagnostics.Append(setConfigMapModel(ctx, &plan, configMap)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setIDIdentity(ctx, resp.Identity, plan.ID)...)
+}
+
+func (r *configMapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state configMapResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, providerDefaultTimeout(r.client, "delete"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+	ctx = brightbox.NewLogContext(ctx, configMapObjectName, state.ID.ValueString(), "delete")
+
+	client := configuredAPIClient(r.client, &resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	tflog.Info(ctx, "Deleting "+configMapObjectName)
+	if _, err := client.DestroyConfigMap(ctx, state.ID.ValueString()); err != nil {
+		addError(&resp.Diagnostics, err)
+		return
+	}
+	tflog.Debug(ctx, "Deleted cleanly")
+}
+
+func (r *configMapResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+}
+
+func (r *configMapResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
+// UpgradeState converts state written by the SDKv2 resource, where
+// data was a map of strings
+func (r *configMapResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior struct {
+					ID   string            `json:"id"`
+					Name string            `json:"name"`
+					Data map[string]string `json:"data"`
+				}
+				if req.RawState == nil || req.RawState.JSON == nil {
+					resp.Diagnostics.AddError("Unable to upgrade Config Map state", "The prior state is not in JSON format")
+					return
+				}
+				if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+					resp.Diagnostics.AddError("Unable to upgrade Config Map state", err.Error())
+					return
+				}
+				data := make(map[string]interface{}, len(prior.Data))
+				for key, value := range prior.Data {
+					data[key] = value
+				}
+				dynamic, err := dynamicFromJSON(data)
+				if err != nil {
+					resp.Diagnostics.AddError("Unable to upgrade Config Map state", err.Error())
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, configMapResourceModel{
+					ID:       types.StringValue(prior.ID),
+					Name:     types.StringValue(prior.Name),
+					Data:     dynamic,
+					Timeouts: timeouts.Value{Object: types.ObjectNull(configMapTimeoutTypes)},
+				})...)
+			},
+		},
+	}
+}
+
+func configMapOptions(ctx context.Context, model configMapResourceModel) (brightboxapi.ConfigMapOptions, error) {
+	data, err := jsonFromDynamic(ctx, model.Data)
+	if err != nil {
+		return brightboxapi.ConfigMapOptions{}, err
+	}
+	name := model.Name.ValueString()
+	return brightboxapi.ConfigMapOptions{
+		Name: &name,
+		Data: &data,
+	}, nil
+}
+
+func setConfigMapModel(ctx context.Context, model *configMapResourceModel, configMap *brightboxapi.ConfigMap) diag.Diagnostics {
+	var diags diag.Diagnostics
+	model.ID = types.StringValue(configMap.ID)
+	model.Name = types.StringValue(configMap.Name)
+	data, err := dataFromAPI(ctx, model.Data, configMap.Data)
+	if err != nil {
+		diags.AddAttributeError(path.Root("data"), "Unable to read Config Map data", err.Error())
+		return diags
+	}
+	model.Data = data
+	return diags
+}
+
+// addError reports err with the same summary and detail as the SDKv2
+// resources
+func addError(diags *diag.Diagnostics, err error) {
+	summary, detail := brightbox.ErrorSummary(err)
+	diags.AddError(summary, detail)
+}
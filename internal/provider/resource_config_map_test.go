@@ -0,0 +1,427 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"testing"
+
+	brightboxapi "github.com/brightbox/gobrightbox/v2"
+	brightbox "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	frameworkresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gotest.tools/v3/assert"
+)
+
+func TestConfigMapDataRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	data := map[string]interface{}{
+		"hostname": "tester",
+		"ram":      float64(1024),
+		"enabled":  true,
+		"tags":     []interface{}{"web", "db"},
+		"nested":   map[string]interface{}{"name": "Admin"},
+	}
+	dynamic, err := dynamicFromJSON(data)
+	assert.NilError(t, err)
+	result, err := jsonFromDynamic(ctx, dynamic)
+	assert.NilError(t, err)
+	assert.Assert(t, equivalentJSON(result, data))
+	assert.Equal(t, result["ram"], int64(1024))
+}
+
+func TestConfigMapDataKeepsPrior(t *testing.T) {
+	ctx := context.Background()
+	prior := types.DynamicValue(types.MapValueMust(types.StringType, map[string]attr.Value{
+		"test": types.StringValue("thing"),
+	}))
+
+	kept, err := dataFromAPI(ctx, prior, map[string]interface{}{"test": "thing"})
+	assert.NilError(t, err)
+	assert.Assert(t, kept.Equal(prior))
+
+	changed, err := dataFromAPI(ctx, prior, map[string]interface{}{"test": "other"})
+	assert.NilError(t, err)
+	assert.Assert(t, !changed.Equal(prior))
+
+	empty, err := dataFromAPI(ctx, types.DynamicNull(), nil)
+	assert.NilError(t, err)
+	result, err := jsonFromDynamic(ctx, empty)
+	assert.NilError(t, err)
+	assert.Equal(t, len(result), 0)
+}
+
+func TestConfigMapUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	r := &configMapResource{}
+	var schemaResp frameworkresource.SchemaResponse
+	r.Schema(ctx, frameworkresource.SchemaRequest{}, &schemaResp)
+	assert.Assert(t, !schemaResp.Diagnostics.HasError())
+
+	req := frameworkresource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id":"cfg-12345","name":"foo","data":{"test":"thing"},"timeouts":null}`),
+		},
+	}
+	resp := frameworkresource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	r.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+	assert.Assert(t, !resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	var model configMapResourceModel
+	assert.Assert(t, !resp.State.Get(ctx, &model).HasError())
+	assert.Equal(t, model.ID.ValueString(), "cfg-12345")
+	assert.Equal(t, model.Name.ValueString(), "foo")
+	data, err := jsonFromDynamic(ctx, model.Data)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, data, map[string]interface{}{"test": "thing"})
+}
+
+func TestAccBrightboxConfigMap_Basic(t *testing.T) {
+	resourceName := "brightbox_config_map.foobar"
+	var configMap brightboxapi.ConfigMap
+	rInt := acctest.RandInt()
+	name := fmt.Sprintf("foo-%d", rInt)
+	updatedName := fmt.Sprintf("bar-%d", rInt)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories(),
+		CheckDestroy:             testAccCheckBrightboxConfigMapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_basic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					testAccCheckBrightboxConfigMapAttributes(&configMap, name),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", name),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_updated(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", updatedName),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBrightboxConfigMap_nested(t *testing.T) {
+	resourceName := "brightbox_config_map.foobar"
+	var configMap brightboxapi.ConfigMap
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories(),
+		CheckDestroy:             testAccCheckBrightboxConfigMapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_nested(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.ram", "1024"),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.admin.name", "Admin"),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.tags.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBrightboxConfigMap_clear_entries(t *testing.T) {
+	resourceName := "brightbox_config_map.foobar"
+	var configMap brightboxapi.ConfigMap
+	rInt := acctest.RandInt()
+	name := fmt.Sprintf("foo-%d", rInt)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories(),
+		CheckDestroy:             testAccCheckBrightboxConfigMapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_basic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					testAccCheckBrightboxConfigMapAttributes(&configMap, name),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", name),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "1"),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_empty_name(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", ""),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "1"),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_empty_data(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", name),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "0"),
+				),
+			},
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_empty,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBrightboxConfigMapExists(resourceName, &configMap),
+					resource.TestCheckResourceAttr(
+						resourceName, "name", ""),
+					resource.TestCheckResourceAttr(
+						resourceName, "data.%", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBrightboxConfigMap_blank(t *testing.T) {
+	resourceName := "brightbox_config_map.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories(),
+		CheckDestroy:             testAccCheckBrightboxConfigMapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBrightboxConfigMapConfig_blank,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestConfigMapValidateData(t *testing.T) {
+	ctx := context.Background()
+	r := &configMapResource{}
+	var schemaResp frameworkresource.SchemaResponse
+	r.Schema(ctx, frameworkresource.SchemaRequest{}, &schemaResp)
+
+	testCases := []struct {
+		name  string
+		data  attr.Value
+		valid bool
+	}{
+		{"object", types.ObjectValueMust(map[string]attr.Type{"test": types.StringType}, map[string]attr.Value{"test": types.StringValue("thing")}), true},
+		{"map", types.MapValueMust(types.StringType, map[string]attr.Value{"test": types.StringValue("thing")}), true},
+		{"string", types.StringValue("thing"), false},
+		{"list", types.ListValueMust(types.StringType, []attr.Value{types.StringValue("thing")}), false},
+	}
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			state := tfsdk.State{Schema: schemaResp.Schema}
+			diags := state.Set(ctx, configMapResourceModel{
+				ID:       types.StringNull(),
+				Name:     types.StringNull(),
+				Data:     types.DynamicValue(tcase.data),
+				Timeouts: timeouts.Value{Object: types.ObjectNull(configMapTimeoutTypes)},
+			})
+			assert.Assert(t, !diags.HasError(), "%v", diags)
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}
+			var resp frameworkresource.ValidateConfigResponse
+			r.ValidateConfig(ctx, frameworkresource.ValidateConfigRequest{Config: config}, &resp)
+			assert.Equal(t, !resp.Diagnostics.HasError(), tcase.valid)
+		})
+	}
+}
+
+func testAccCheckBrightboxConfigMapExists(n string, configMap *brightboxapi.ConfigMap) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Config Map ID is set")
+		}
+		client, err := testAccProvider.Meta().(*brightbox.CompositeClient).APIClient()
+		if err != nil {
+			return err
+		}
+		retrieved, err := client.ConfigMap(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		*configMap = *retrieved
+		return nil
+	}
+}
+
+func testAccCheckBrightboxConfigMapDestroy(s *terraform.State) error {
+	client, err := testAccProvider.Meta().(*brightbox.CompositeClient).APIClient()
+	if err != nil {
+		return err
+	}
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "brightbox_config_map" {
+			continue
+		}
+		_, err := client.ConfigMap(context.Background(), rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Config Map %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccCheckBrightboxConfigMapAttributes(configMap *brightboxapi.ConfigMap, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if configMap.Name != name {
+			return fmt.Errorf("Bad name: %s", configMap.Name)
+		}
+		return nil
+	}
+}
+
+func testAccCheckBrightboxConfigMapConfig_basic(rInt int) string {
+	return fmt.Sprintf(`
+
+resource "brightbox_config_map" "foobar" {
+	name = "foo-%d"
+	data = {"test": "thing-%d"}
+}
+`, rInt, rInt)
+}
+
+func testAccCheckBrightboxConfigMapConfig_nested(rInt int) string {
+	return fmt.Sprintf(`
+
+resource "brightbox_config_map" "foobar" {
+	name = "foo-%d"
+	data = {
+		ram   = 1024
+		admin = { name = "Admin" }
+		tags  = ["web", "db"]
+	}
+}
+`, rInt)
+}
+
+func testAccCheckBrightboxConfigMapConfig_empty_name(rInt int) string {
+	return fmt.Sprintf(`
+
+resource "brightbox_config_map" "foobar" {
+	name = ""
+	data = {"test": "thing-%d"}
+}
+`, rInt)
+}
+
+func testAccCheckBrightboxConfigMapConfig_empty_data(rInt int) string {
+	return fmt.Sprintf(`
+
+resource "brightbox_config_map" "foobar" {
+	name = "foo-%d"
+	data = { }
+}
+`, rInt)
+}
+
+func testAccCheckBrightboxConfigMapConfig_updated(rInt int) string {
+	return fmt.Sprintf(`
+
+resource "brightbox_config_map" "foobar" {
+	name = "bar-%d"
+	data = { "test": "bar-%d", "test2": "foo-%d" }
+}
+`, rInt, rInt, rInt)
+}
+
+const testAccCheckBrightboxConfigMapConfig_empty = `
+
+resource "brightbox_config_map" "foobar" {
+	name = ""
+	data = {}
+}
+`
+const testAccCheckBrightboxConfigMapConfig_blank = `
+
+resource "brightbox_config_map" "foobar" {
+	data = { "thing": "{ \"name\" : \"Admin\" }" }
+	}
+`
+
+// Sweeper
+
+var testNameRe = regexp.MustCompile(`^foo-\d+|^bar-\d+|^baz-\d+|^initial$`)
+
+func init() {
+	resource.AddTestSweepers("config_map", &resource.Sweeper{
+		Name: "config_map",
+		F: func(_ string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sdk := brightbox.Provider("test")
+			diags := sdk.Configure(ctx, terraform.NewResourceConfigRaw(nil))
+			if diags.HasError() {
+				return fmt.Errorf("%s", diags[0].Summary)
+			}
+			apiClient, err := sdk.Meta().(*brightbox.CompositeClient).APIClient()
+			if err != nil {
+				return err
+			}
+			objects, err := apiClient.ConfigMaps(ctx)
+			if err != nil {
+				return err
+			}
+			for _, object := range objects {
+				if testNameRe.MatchString(object.Name) {
+					log.Printf("[INFO] removing %s named %s", object.ID, object.Name)
+					if _, err := apiClient.DestroyConfigMap(ctx, object.ID); err != nil {
+						log.Printf("error destroying %s during sweep: %s", object.ID, err)
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
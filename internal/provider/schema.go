@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// providerSchema converts the protocol schema of the SDKv2 provider
+// into the equivalent framework schema
+func providerSchema(in *tfprotov5.Schema) (schema.Schema, error) {
+	result := schema.Schema{
+		Attributes: map[string]schema.Attribute{},
+	}
+	if in == nil || in.Block == nil {
+		return result, nil
+	}
+	if len(in.Block.BlockTypes) > 0 {
+		return result, fmt.Errorf("nested blocks are not supported in the provider schema")
+	}
+	result.Description, result.MarkdownDescription = descriptions(in.Block.Description, in.Block.DescriptionKind)
+	if in.Block.Deprecated {
+		result.DeprecationMessage = "Deprecated"
+	}
+	for _, attribute := range in.Block.Attributes {
+		converted, err := providerAttribute(attribute)
+		if err != nil {
+			return result, fmt.Errorf("attribute %q: %w", attribute.Name, err)
+		}
+		result.Attributes[attribute.Name] = converted
+	}
+	return result, nil
+}
+
+func providerAttribute(in *tfprotov5.SchemaAttribute) (schema.Attribute, error) {
+	description, markdown := descriptions(in.Description, in.DescriptionKind)
+	var deprecation string
+	if in.Deprecated {
+		deprecation = "Deprecated"
+	}
+	switch {
+	case in.Type.Is(tftypes.String):
+		return schema.StringAttribute{
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case in.Type.Is(tftypes.Bool):
+		return schema.BoolAttribute{
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case in.Type.Is(tftypes.Number):
+		return schema.NumberAttribute{
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case in.Type.Is(tftypes.Set{}):
+		element, err := attrType(in.Type.(tftypes.Set).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return schema.SetAttribute{
+			ElementType:         element,
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case in.Type.Is(tftypes.List{}):
+		element, err := attrType(in.Type.(tftypes.List).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return schema.ListAttribute{
+			ElementType:         element,
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case in.Type.Is(tftypes.Map{}):
+		element, err := attrType(in.Type.(tftypes.Map).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return schema.MapAttribute{
+			ElementType:         element,
+			Required:            in.Required,
+			Optional:            in.Optional,
+			Sensitive:           in.Sensitive,
+			Description:         description,
+			MarkdownDescription: markdown,
+			DeprecationMessage:  deprecation,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", in.Type)
+}
+
+func attrType(in tftypes.Type) (attr.Type, error) {
+	switch {
+	case in.Is(tftypes.String):
+		return types.StringType, nil
+	case in.Is(tftypes.Bool):
+		return types.BoolType, nil
+	case in.Is(tftypes.Number):
+		return types.NumberType, nil
+	}
+	return nil, fmt.Errorf("unsupported element type %s", in)
+}
+
+// descriptions splits a protocol description into the plain and
+// markdown descriptions of a framework schema
+func descriptions(description string, kind tfprotov5.StringKind) (string, string) {
+	if kind == tfprotov5.StringKindMarkdown {
+		return "", description
+	}
+	return description, ""
+}
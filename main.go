@@ -6,6 +6,8 @@ import (
 	"log"
 
 	sdkprovider "github.com/brightbox/terraform-provider-brightbox/brightbox"
+	"github.com/brightbox/terraform-provider-brightbox/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
@@ -26,9 +28,12 @@ func main() {
 		opts = append(opts, tf5server.WithManagedDebug())
 	}
 
+	// The SDKv2 provider must come first: the mux configures providers
+	// in order and the framework provider reuses its client.
+	sdk := sdkprovider.Provider(version)
 	providers := []func() tfprotov5.ProviderServer{
-		//providerserver.NewProtocol5(provider.New(version)),
-		sdkprovider.Provider().GRPCProvider,
+		sdk.GRPCProvider,
+		providerserver.NewProtocol5(provider.New(version, sdk)()),
 	}
 
 	// use the muxer